@@ -0,0 +1,127 @@
+package uniwidth
+
+// The ASCII fast path of StringWidth checks that a string is ASCII-only and
+// counts its non-control bytes. isASCIIOnly and asciiWidth dispatch to the
+// fastest implementation for the platform: SSE2 or AVX2 on amd64, NEON on
+// arm64 (see ascii_amd64.go, ascii_arm64.go), and the portable SWAR versions
+// below everywhere else, for short strings, and in builds with the purego
+// tag. The SWAR versions read words with load64, which only uses unsafe
+// without the purego tag.
+
+// isASCIIOnlySWAR returns true if the string contains only ASCII characters (0x00-0x7F).
+//
+// Uses SWAR (SIMD Within A Register) to process 8 bytes at a time by loading
+// them into a uint64 and checking all high bits simultaneously with a single
+// AND against 0x8080808080808080. If any byte has its high bit set (>= 0x80),
+// it is non-ASCII. This works regardless of endianness because we only test
+// whether any byte has its high bit set, not which byte it is.
+//
+// Performance:
+//   - Short strings (< 8 bytes): scalar fallback, O(n) per byte
+//   - Longer strings: ~8x throughput via SWAR, O(n/8) per word + O(n%8) tail
+//   - 0 allocations in all cases
+func isASCIIOnlySWAR(s string) bool {
+	n := len(s)
+	if n == 0 {
+		return true
+	}
+
+	// SWAR: process 8 bytes at a time
+	const asciiMask = uint64(0x8080808080808080)
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		word := load64(s, i)
+		if word&asciiMask != 0 {
+			return false
+		}
+	}
+
+	// Scalar tail: process remaining bytes (0-7)
+	for ; i < n; i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// asciiWidthSWAR returns the visual width of an ASCII-only string, accounting for
+// control characters (0x00-0x1F, 0x7F) which have zero width.
+//
+// Uses SWAR to detect control characters in 8-byte chunks. If a chunk contains
+// no control characters, width += 8 directly. Otherwise, falls back to scalar
+// processing for that chunk.
+//
+// Control character detection uses Daniel Lemire's SWAR technique:
+//   - Bytes < 0x20: detected via (x - 0x2020...) & ~x & 0x8080...
+//   - Byte == 0x7F: detected via XOR with 0x7F7F... then same underflow trick
+//
+// The underflow trick works because subtracting 0x20 from a byte < 0x20 causes
+// the high bit to set (unsigned underflow), while the original byte had its high
+// bit clear. The AND with ~x isolates genuine underflows from bytes >= 0x80
+// (which cannot appear here since isASCIIOnly was already verified).
+//
+// Caller must ensure s contains only ASCII bytes (call isASCIIOnly first).
+//
+// Performance:
+//   - 0 allocations
+//   - ~8x throughput for chunks without control characters
+func asciiWidthSWAR(s string) int {
+	n := len(s)
+	if n == 0 {
+		return 0
+	}
+
+	width := 0
+	i := 0
+
+	// SWAR constants for control character detection.
+	const (
+		// Broadcast 0x20 and 0x7F across all 8 bytes of a uint64.
+		lo20  = uint64(0x2020202020202020)
+		hi80  = uint64(0x8080808080808080)
+		rep7F = uint64(0x7F7F7F7F7F7F7F7F)
+		rep01 = uint64(0x0101010101010101)
+	)
+
+	// Process 8 bytes at a time
+	for ; i+8 <= n; i += 8 {
+		word := load64(s, i)
+
+		// Detect bytes < 0x20 using SWAR underflow trick:
+		// (word - 0x2020...) produces underflow (sets high bit) for bytes < 0x20.
+		// &^word masks out bytes that already had high bit set (not possible for
+		// ASCII, but defensive). &hi80 extracts only the high bits.
+		hasLow := (word - lo20) & ^word & hi80
+
+		// Detect bytes == 0x7F using XOR + underflow:
+		// word ^ 0x7F7F... zeros out any 0x7F bytes. Then the zero-byte detection
+		// pattern ((v - 0x0101...) & ~v & 0x8080...) finds the zeroed positions.
+		xored := word ^ rep7F
+		has7F := (xored - rep01) & ^xored & hi80
+
+		if (hasLow | has7F) == 0 {
+			// Fast path: no control characters in this 8-byte chunk
+			width += 8
+		} else {
+			// Slow path: at least one control character, process byte by byte
+			for j := 0; j < 8; j++ {
+				b := s[i+j]
+				if b >= 0x20 && b != 0x7F {
+					width++
+				}
+			}
+		}
+	}
+
+	// Scalar tail: process remaining bytes (0-7)
+	for ; i < n; i++ {
+		b := s[i]
+		if b >= 0x20 && b != 0x7F {
+			width++
+		}
+	}
+
+	return width
+}
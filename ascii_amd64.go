@@ -0,0 +1,119 @@
+//go:build !purego
+
+package uniwidth
+
+// simdMinLen is the length from which the assembly implementations beat
+// SWAR; below it, the call overhead dominates.
+const simdMinLen = 32
+
+// hasAVX2 reports whether the CPU and the operating system support AVX2.
+// SSE2 is part of the amd64 baseline and needs no check.
+var hasAVX2 = cpuHasAVX2()
+
+// cpuid executes the CPUID instruction for the given leaf and subleaf.
+//
+//go:noescape
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv returns the low and high half of extended control register XCR0.
+//
+//go:noescape
+func xgetbv() (eax, edx uint32)
+
+// anyHighBitSSE2 reports whether any byte of s is >= 0x80, 16 bytes at a
+// time. len(s) must be a multiple of 16.
+//
+//go:noescape
+func anyHighBitSSE2(s string) bool
+
+// countControlSSE2 returns the number of bytes of s that are < 0x20 or 0x7F,
+// 16 bytes at a time. len(s) must be a multiple of 16.
+//
+//go:noescape
+func countControlSSE2(s string) int
+
+// anyHighBitAVX2 is anyHighBitSSE2 for 32 bytes at a time. len(s) must be a
+// multiple of 32, and the CPU must support AVX2.
+//
+//go:noescape
+func anyHighBitAVX2(s string) bool
+
+// countControlAVX2 is countControlSSE2 for 32 bytes at a time. len(s) must
+// be a multiple of 32, and the CPU must support AVX2.
+//
+//go:noescape
+func countControlAVX2(s string) int
+
+// cpuHasAVX2 detects AVX2 with CPUID. The OS must also have enabled the
+// XSAVE state of the YMM registers, or AVX instructions fault.
+func cpuHasAVX2() bool {
+	const (
+		osxsave = 1 << 27 // CPUID.1:ECX
+		avx     = 1 << 28 // CPUID.1:ECX
+		avx2    = 1 << 5  // CPUID.(7,0):EBX
+		xmmYmm  = 0b110   // XCR0: SSE and AVX state
+	)
+
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	if ecx1&osxsave == 0 || ecx1&avx == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&xmmYmm != xmmYmm {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&avx2 != 0
+}
+
+// isASCIIOnly reports whether s contains only ASCII bytes (0x00-0x7F).
+func isASCIIOnly(s string) bool {
+	switch {
+	case len(s) < simdMinLen:
+		return isASCIIOnlySWAR(s)
+	case hasAVX2:
+		return isASCIIOnlyAVX2(s)
+	default:
+		return isASCIIOnlySSE2(s)
+	}
+}
+
+// asciiWidth returns the number of bytes of s that are not control
+// characters (0x00-0x1F, 0x7F), the width of an ASCII-only string.
+func asciiWidth(s string) int {
+	switch {
+	case len(s) < simdMinLen:
+		return asciiWidthSWAR(s)
+	case hasAVX2:
+		return asciiWidthAVX2(s)
+	default:
+		return asciiWidthSSE2(s)
+	}
+}
+
+// isASCIIOnlySSE2 checks 16-byte blocks with SSE2 and the tail with SWAR.
+func isASCIIOnlySSE2(s string) bool {
+	n := len(s) &^ 15
+	return !anyHighBitSSE2(s[:n]) && isASCIIOnlySWAR(s[n:])
+}
+
+// asciiWidthSSE2 counts 16-byte blocks with SSE2 and the tail with SWAR.
+func asciiWidthSSE2(s string) int {
+	n := len(s) &^ 15
+	return n - countControlSSE2(s[:n]) + asciiWidthSWAR(s[n:])
+}
+
+// isASCIIOnlyAVX2 checks 32-byte blocks with AVX2 and the tail with SWAR.
+func isASCIIOnlyAVX2(s string) bool {
+	n := len(s) &^ 31
+	return !anyHighBitAVX2(s[:n]) && isASCIIOnlySWAR(s[n:])
+}
+
+// asciiWidthAVX2 counts 32-byte blocks with AVX2 and the tail with SWAR.
+func asciiWidthAVX2(s string) int {
+	n := len(s) &^ 31
+	return n - countControlAVX2(s[:n]) + asciiWidthSWAR(s[n:])
+}
@@ -0,0 +1,152 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL	eaxArg+0(FP), AX
+	MOVL	ecxArg+4(FP), CX
+	CPUID
+	MOVL	AX, eax+8(FP)
+	MOVL	BX, ebx+12(FP)
+	MOVL	CX, ecx+16(FP)
+	MOVL	DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL	$0, CX
+	XGETBV
+	MOVL	AX, eax+0(FP)
+	MOVL	DX, edx+4(FP)
+	RET
+
+// func anyHighBitSSE2(s string) bool
+TEXT ·anyHighBitSSE2(SB), NOSPLIT, $0-17
+	MOVQ	s_base+0(FP), SI
+	MOVQ	s_len+8(FP), CX
+	ADDQ	SI, CX // CX = end of s
+
+loop:
+	CMPQ	SI, CX
+	JEQ	ascii
+	MOVOU	(SI), X0
+	ADDQ	$16, SI
+	PMOVMSKB	X0, AX // one bit per byte: its high bit
+	TESTL	AX, AX
+	JZ	loop
+	MOVB	$1, ret+16(FP)
+	RET
+
+ascii:
+	MOVB	$0, ret+16(FP)
+	RET
+
+// func countControlSSE2(s string) int
+TEXT ·countControlSSE2(SB), NOSPLIT, $0-24
+	MOVQ	s_base+0(FP), SI
+	MOVQ	s_len+8(FP), CX
+	ADDQ	SI, CX // CX = end of s
+
+	MOVQ	$0x1f1f1f1f1f1f1f1f, AX
+	MOVQ	AX, X1
+	PUNPCKLQDQ	X1, X1 // X1 = 0x1F in every byte
+	MOVQ	$0x7f7f7f7f7f7f7f7f, AX
+	MOVQ	AX, X2
+	PUNPCKLQDQ	X2, X2 // X2 = 0x7F in every byte
+	MOVQ	$0x0101010101010101, AX
+	MOVQ	AX, X3
+	PUNPCKLQDQ	X3, X3 // X3 = 0x01 in every byte
+	PXOR	X4, X4 // X4 = 0
+	PXOR	X5, X5 // X5 = control counts, one per 64-bit half
+
+loop:
+	CMPQ	SI, CX
+	JEQ	done
+	MOVOU	(SI), X0
+	ADDQ	$16, SI
+
+	// Bytes <= 0x1F are those equal to their unsigned minimum with 0x1F.
+	MOVO	X0, X6
+	PMINUB	X1, X6
+	PCMPEQB	X0, X6 // X6 = 0xFF where byte < 0x20
+	PCMPEQB	X2, X0 // X0 = 0xFF where byte == 0x7F
+	POR	X6, X0
+	PAND	X3, X0 // 1 per control byte
+	PSADBW	X4, X0 // sum the bytes of each half
+	PADDQ	X0, X5
+	JMP	loop
+
+done:
+	PSHUFD	$0x4e, X5, X0 // swap the halves
+	PADDQ	X0, X5
+	MOVQ	X5, AX
+	MOVQ	AX, ret+16(FP)
+	RET
+
+// func anyHighBitAVX2(s string) bool
+TEXT ·anyHighBitAVX2(SB), NOSPLIT, $0-17
+	MOVQ	s_base+0(FP), SI
+	MOVQ	s_len+8(FP), CX
+	ADDQ	SI, CX // CX = end of s
+
+loop:
+	CMPQ	SI, CX
+	JEQ	ascii
+	VMOVDQU	(SI), Y0
+	ADDQ	$32, SI
+	VPMOVMSKB	Y0, AX // one bit per byte: its high bit
+	TESTL	AX, AX
+	JZ	loop
+	VZEROUPPER
+	MOVB	$1, ret+16(FP)
+	RET
+
+ascii:
+	VZEROUPPER
+	MOVB	$0, ret+16(FP)
+	RET
+
+// func countControlAVX2(s string) int
+TEXT ·countControlAVX2(SB), NOSPLIT, $0-24
+	MOVQ	s_base+0(FP), SI
+	MOVQ	s_len+8(FP), CX
+	ADDQ	SI, CX // CX = end of s
+
+	MOVQ	$0x1f1f1f1f1f1f1f1f, AX
+	VMOVQ	AX, X1 // VEX encoded: legacy SSE after 256-bit ops stalls
+	VPBROADCASTQ	X1, Y1 // Y1 = 0x1F in every byte
+	MOVQ	$0x7f7f7f7f7f7f7f7f, AX
+	VMOVQ	AX, X2
+	VPBROADCASTQ	X2, Y2 // Y2 = 0x7F in every byte
+	MOVQ	$0x0101010101010101, AX
+	VMOVQ	AX, X3
+	VPBROADCASTQ	X3, Y3 // Y3 = 0x01 in every byte
+	VPXOR	Y4, Y4, Y4 // Y4 = 0
+	VPXOR	Y5, Y5, Y5 // Y5 = control counts, one per 64-bit lane
+
+loop:
+	CMPQ	SI, CX
+	JEQ	done
+	VMOVDQU	(SI), Y0
+	ADDQ	$32, SI
+
+	// Bytes <= 0x1F are those equal to their unsigned minimum with 0x1F.
+	VPMINUB	Y1, Y0, Y6
+	VPCMPEQB	Y0, Y6, Y6 // Y6 = 0xFF where byte < 0x20
+	VPCMPEQB	Y2, Y0, Y0 // Y0 = 0xFF where byte == 0x7F
+	VPOR	Y6, Y0, Y0
+	VPAND	Y3, Y0, Y0 // 1 per control byte
+	VPSADBW	Y4, Y0, Y0 // sum the bytes of each lane
+	VPADDQ	Y0, Y5, Y5
+	JMP	loop
+
+done:
+	VEXTRACTI128	$1, Y5, X0
+	VPADDQ	X0, X5, X5 // fold the upper 128 bits
+	VPSHUFD	$0x4e, X5, X0
+	VPADDQ	X0, X5, X5 // fold the upper 64 bits
+	VMOVQ	X5, AX
+	VZEROUPPER
+	MOVQ	AX, ret+16(FP)
+	RET
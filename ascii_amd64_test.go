@@ -0,0 +1,15 @@
+//go:build !purego
+
+package uniwidth
+
+// asciiImpls returns the implementations the CPU running the tests supports.
+func asciiImpls() []asciiImpl {
+	impls := []asciiImpl{
+		{"SWAR", isASCIIOnlySWAR, asciiWidthSWAR},
+		{"SSE2", isASCIIOnlySSE2, asciiWidthSSE2},
+	}
+	if hasAVX2 {
+		impls = append(impls, asciiImpl{"AVX2", isASCIIOnlyAVX2, asciiWidthAVX2})
+	}
+	return impls
+}
@@ -0,0 +1,51 @@
+//go:build !purego
+
+package uniwidth
+
+// simdMinLen is the length from which the assembly implementations beat
+// SWAR; below it, the call overhead dominates.
+//
+// NEON (Advanced SIMD) is part of the arm64 base architecture, so unlike on
+// amd64 there is nothing to detect at run time.
+const simdMinLen = 32
+
+// anyHighBitNEON reports whether any byte of s is >= 0x80, 32 bytes at a
+// time. len(s) must be a multiple of 32.
+//
+//go:noescape
+func anyHighBitNEON(s string) bool
+
+// countControlNEON returns the number of bytes of s that are < 0x20 or 0x7F,
+// 32 bytes at a time. len(s) must be a multiple of 32.
+//
+//go:noescape
+func countControlNEON(s string) int
+
+// isASCIIOnly reports whether s contains only ASCII bytes (0x00-0x7F).
+func isASCIIOnly(s string) bool {
+	if len(s) < simdMinLen {
+		return isASCIIOnlySWAR(s)
+	}
+	return isASCIIOnlyNEON(s)
+}
+
+// asciiWidth returns the number of bytes of s that are not control
+// characters (0x00-0x1F, 0x7F), the width of an ASCII-only string.
+func asciiWidth(s string) int {
+	if len(s) < simdMinLen {
+		return asciiWidthSWAR(s)
+	}
+	return asciiWidthNEON(s)
+}
+
+// isASCIIOnlyNEON checks 32-byte blocks with NEON and the tail with SWAR.
+func isASCIIOnlyNEON(s string) bool {
+	n := len(s) &^ 31
+	return !anyHighBitNEON(s[:n]) && isASCIIOnlySWAR(s[n:])
+}
+
+// asciiWidthNEON counts 32-byte blocks with NEON and the tail with SWAR.
+func asciiWidthNEON(s string) int {
+	n := len(s) &^ 31
+	return n - countControlNEON(s[:n]) + asciiWidthSWAR(s[n:])
+}
@@ -0,0 +1,60 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func anyHighBitNEON(s string) bool
+TEXT ·anyHighBitNEON(SB), NOSPLIT, $0-17
+	MOVD	s_base+0(FP), R0
+	MOVD	s_len+8(FP), R1
+	ADD	R0, R1, R2 // R2 = end of s
+
+loop:
+	CMP	R0, R2
+	BEQ	ascii
+	VLD1.P	(R0), [V0.B16, V1.B16]
+	VORR	V0.B16, V1.B16, V2.B16
+	VMOV	V2.D[0], R3
+	VMOV	V2.D[1], R4
+	ORR	R3, R4, R3
+	TST	$0x8080808080808080, R3
+	BEQ	loop
+	MOVD	$1, R3
+	MOVB	R3, ret+16(FP)
+	RET
+
+ascii:
+	MOVB	ZR, ret+16(FP)
+	RET
+
+// func countControlNEON(s string) int
+TEXT ·countControlNEON(SB), NOSPLIT, $0-24
+	MOVD	s_base+0(FP), R0
+	MOVD	s_len+8(FP), R1
+	ADD	R0, R1, R2 // R2 = end of s
+
+	VMOVI	$0x20, V20.B16
+	VMOVI	$0x7f, V21.B16
+	VMOVI	$1, V22.B16
+	VEOR	V8.B16, V8.B16, V8.B16 // V8 = control count in its low 64 bits
+
+loop:
+	CMP	R0, R2
+	BEQ	done
+	VLD1.P	(R0), [V0.B16, V1.B16]
+	VCMHI	V0.B16, V20.B16, V2.B16 // V2 = 0xFF where 0x20 > byte
+	VCMHI	V1.B16, V20.B16, V3.B16
+	VCMEQ	V21.B16, V0.B16, V4.B16 // V4 = 0xFF where byte == 0x7F
+	VCMEQ	V21.B16, V1.B16, V5.B16
+	VORR	V4.B16, V2.B16, V2.B16
+	VORR	V5.B16, V3.B16, V3.B16
+	VAND	V22.B16, V2.B16, V2.B16 // 1 per control byte
+	VAND	V22.B16, V3.B16, V3.B16
+	VADD	V3.B16, V2.B16, V2.B16 // at most 2 per byte
+	VUADDLV	V2.B16, V7
+	VADD	V7, V8
+	B	loop
+
+done:
+	VMOV	V8.D[0], R3
+	MOVD	R3, ret+16(FP)
+	RET
@@ -0,0 +1,11 @@
+//go:build !purego
+
+package uniwidth
+
+// asciiImpls returns the implementations of the ASCII fast path.
+func asciiImpls() []asciiImpl {
+	return []asciiImpl{
+		{"SWAR", isASCIIOnlySWAR, asciiWidthSWAR},
+		{"NEON", isASCIIOnlyNEON, asciiWidthNEON},
+	}
+}
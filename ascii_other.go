@@ -0,0 +1,14 @@
+//go:build (!amd64 && !arm64) || purego
+
+package uniwidth
+
+// isASCIIOnly reports whether s contains only ASCII bytes (0x00-0x7F).
+func isASCIIOnly(s string) bool {
+	return isASCIIOnlySWAR(s)
+}
+
+// asciiWidth returns the number of bytes of s that are not control
+// characters (0x00-0x1F, 0x7F), the width of an ASCII-only string.
+func asciiWidth(s string) int {
+	return asciiWidthSWAR(s)
+}
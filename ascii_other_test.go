@@ -0,0 +1,10 @@
+//go:build (!amd64 && !arm64) || purego
+
+package uniwidth
+
+// asciiImpls returns the implementations of the ASCII fast path.
+func asciiImpls() []asciiImpl {
+	return []asciiImpl{
+		{"SWAR", isASCIIOnlySWAR, asciiWidthSWAR},
+	}
+}
@@ -0,0 +1,100 @@
+package uniwidth
+
+import (
+	"strings"
+	"testing"
+)
+
+// asciiImpl is one implementation of isASCIIOnly and asciiWidth.
+type asciiImpl struct {
+	name        string
+	isASCIIOnly func(string) bool
+	asciiWidth  func(string) int
+}
+
+// refIsASCIIOnly and refASCIIWidth are the byte-at-a-time definitions that
+// every implementation must match, for any bytes.
+func refIsASCIIOnly(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func refASCIIWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x20 && s[i] != 0x7F {
+			width++
+		}
+	}
+	return width
+}
+
+// checkASCIIImpls reports every implementation that disagrees with the
+// reference on s.
+func checkASCIIImpls(t *testing.T, s string) {
+	t.Helper()
+	wantASCII, wantWidth := refIsASCIIOnly(s), refASCIIWidth(s)
+	for _, impl := range asciiImpls() {
+		if got := impl.isASCIIOnly(s); got != wantASCII {
+			t.Errorf("isASCIIOnly%s(%q) = %v, want %v", impl.name, s, got, wantASCII)
+		}
+		if got := impl.asciiWidth(s); got != wantWidth {
+			t.Errorf("asciiWidth%s(%q) = %d, want %d", impl.name, s, got, wantWidth)
+		}
+	}
+	if got := isASCIIOnly(s); got != wantASCII {
+		t.Errorf("isASCIIOnly(%q) = %v, want %v", s, got, wantASCII)
+	}
+	if got := asciiWidth(s); got != wantWidth {
+		t.Errorf("asciiWidth(%q) = %d, want %d", s, got, wantWidth)
+	}
+}
+
+// TestASCIIImpls checks every implementation against the reference across
+// the block sizes (8, 16 and 32 bytes) and their tails: each length up to
+// 130 bytes, with a special byte at every position, at every alignment.
+func TestASCIIImpls(t *testing.T) {
+	specials := []byte{0x00, 0x1F, 0x20, 0x7E, 0x7F, 0x80, 0x9F, 0xE4, 0xFF}
+
+	buf := []byte(strings.Repeat("The quick brown fox jumps over the lazy dog. ", 4))
+	for n := 0; n <= 130; n++ {
+		for off := 0; off < 4; off++ {
+			base := buf[off : off+n]
+			checkASCIIImpls(t, string(base))
+
+			for pos := range n {
+				for _, b := range specials {
+					s := []byte(string(base))
+					s[pos] = b
+					checkASCIIImpls(t, string(s))
+				}
+			}
+		}
+	}
+}
+
+// TestASCIIImpls_AllBytes checks every byte value in every lane of a block,
+// and a string made only of control characters.
+func TestASCIIImpls_AllBytes(t *testing.T) {
+	for b := range 256 {
+		checkASCIIImpls(t, strings.Repeat(string([]byte{byte(b)}), 100))
+	}
+
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	checkASCIIImpls(t, string(all))
+	checkASCIIImpls(t, string(all[:0x80]))
+}
+
+// TestASCIIImpls_Long checks counts far beyond one accumulator lane.
+func TestASCIIImpls_Long(t *testing.T) {
+	checkASCIIImpls(t, strings.Repeat("\x00", 1<<16))
+	checkASCIIImpls(t, strings.Repeat("a\tb\x7f", 1<<15))
+	checkASCIIImpls(t, strings.Repeat("x", 1<<16)+"é")
+}
@@ -1,6 +1,8 @@
 package uniwidth
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -181,6 +183,29 @@ func BenchmarkIsASCIIOnly_Short_NonASCII(b *testing.B) {
 	}
 }
 
+// BenchmarkASCIIImpls compares the ASCII fast path implementations on log
+// lines of increasing length.
+func BenchmarkASCIIImpls(b *testing.B) {
+	line := "2026-02-05T10:15:30Z INFO request completed method=GET path=/api/v1/items status=200\t"
+	for _, n := range []int{16, 32, 64, 256, 4096} {
+		s := strings.Repeat(line, n/len(line)+1)[:n]
+		for _, impl := range asciiImpls() {
+			b.Run(fmt.Sprintf("isASCIIOnly/%s/%d", impl.name, n), func(b *testing.B) {
+				b.SetBytes(int64(n))
+				for b.Loop() {
+					_ = impl.isASCIIOnly(s)
+				}
+			})
+			b.Run(fmt.Sprintf("asciiWidth/%s/%d", impl.name, n), func(b *testing.B) {
+				b.SetBytes(int64(n))
+				for b.Loop() {
+					_ = impl.asciiWidth(s)
+				}
+			})
+		}
+	}
+}
+
 // ============================================================================
 // Benchmark: Real-world TUI scenarios
 // ============================================================================
@@ -211,3 +236,44 @@ func BenchmarkStringWidth_TUI_StatusLine(b *testing.B) {
 		_ = StringWidth(s)
 	}
 }
+
+// Progress bar frames overwriting each other
+func BenchmarkRenderedWidth_ProgressBar(b *testing.B) {
+	s := "⏳ [#####     ] 50%\r\x1b[K⏳ [######    ] 60%\r\x1b[K✅ [##########] done"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = RenderedWidth(s)
+	}
+}
+
+func BenchmarkLineIndex_Insert(b *testing.B) {
+	// A keystroke in the middle of a 1 MB document of 73-byte lines.
+	x := NewLineIndex(strings.Repeat(strings.Repeat("Hello, 世界! 👋🏽 ", 3)+"\n", 1<<14))
+	offset := x.Len() / 2
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Insert(offset, "x")
+		x.Delete(offset, offset+1)
+	}
+}
+
+func BenchmarkLineIndex_InsertLongLine(b *testing.B) {
+	// A keystroke in the middle of a 1 MB single-line document, which is
+	// one block.
+	x := NewLineIndex(strings.Repeat("Hello, 世界! 👋🏽 ", 1<<16))
+	offset := x.Len() / 2
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Insert(offset, "x")
+		x.Delete(offset, offset+1)
+	}
+}
+
+func BenchmarkLineIndex_Position(b *testing.B) {
+	x := NewLineIndex(strings.Repeat("Hello, 世界! 👋🏽 ", 1<<16))
+	offset := x.Len() / 2
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = x.Position(offset)
+	}
+}
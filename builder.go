@@ -0,0 +1,180 @@
+package uniwidth
+
+import "strings"
+
+// Builder builds a string with strings.Builder while keeping track of its
+// display width, for status bars and prompts assembled from many segments
+// that must fit in the terminal.
+//
+// The width is kept up to date incrementally, but always equals the width of
+// the whole string: a segment that continues the last cluster is measured
+// with it, so "👨" followed by "\u200d👩" is one width-2 emoji, and "e"
+// followed by "\u0301" one column.
+//
+// Example:
+//
+//	b := uniwidth.NewBuilder(20)
+//	b.WriteString("✅ build ")
+//	b.TryWrite("passed in 1.2s")            // false: would need 23 columns
+//	b.WriteTruncated("passed in 1.2s", "…") // "✅ build passed in …"
+//	b.Width(), b.Remaining()                // 20, 0
+//
+// The zero Builder has no column limit and measures like StringWidth. A
+// Builder must not be copied after first use.
+type Builder struct {
+	sb      strings.Builder
+	o       *Options
+	limit   int
+	limited bool
+
+	// The clusters before sb.String()[tail:] can no longer change and are
+	// head columns wide; the string as a whole is width columns wide.
+	tail  int
+	head  int
+	width int
+}
+
+// NewBuilder returns a Builder whose TryWrite and WriteTruncated keep the
+// string within limit columns, measuring text with opts.
+//
+// Example:
+//
+//	b := uniwidth.NewBuilder(80, uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide))
+func NewBuilder(limit int, opts ...Option) *Builder {
+	return &Builder{o: clusterOptions(opts), limit: max(limit, 0), limited: true}
+}
+
+// String returns the accumulated string.
+func (b *Builder) String() string {
+	return b.sb.String()
+}
+
+// Len returns the number of accumulated bytes.
+func (b *Builder) Len() int {
+	return b.sb.Len()
+}
+
+// Width returns the display width of the accumulated string.
+func (b *Builder) Width() int {
+	return b.width
+}
+
+// Remaining returns the number of columns left before the limit. It is
+// negative if the Write methods, which ignore the limit, went past it, and
+// the largest int for a Builder without a limit.
+func (b *Builder) Remaining() int {
+	if !b.limited {
+		return maxInt
+	}
+	return b.limit - b.width
+}
+
+// Grow grows the builder's capacity to fit another n bytes.
+func (b *Builder) Grow(n int) {
+	b.sb.Grow(n)
+}
+
+// Reset empties the builder, keeping its limit and options.
+func (b *Builder) Reset() {
+	b.sb.Reset()
+	b.tail, b.head, b.width = 0, 0, 0
+}
+
+// Write appends p, whatever its width. It always returns len(p), nil.
+func (b *Builder) Write(p []byte) (int, error) {
+	b.sb.Write(p)
+	b.measure()
+	return len(p), nil
+}
+
+// WriteString appends s, whatever its width. It always returns len(s), nil.
+func (b *Builder) WriteString(s string) (int, error) {
+	b.sb.WriteString(s)
+	b.measure()
+	return len(s), nil
+}
+
+// WriteByte appends c, whatever its width. It always returns nil.
+func (b *Builder) WriteByte(c byte) error {
+	b.sb.WriteByte(c)
+	b.measure()
+	return nil
+}
+
+// WriteRune appends the UTF-8 encoding of r, whatever its width. It always
+// returns its length and nil.
+func (b *Builder) WriteRune(r rune) (int, error) {
+	n, _ := b.sb.WriteRune(r)
+	b.measure()
+	return n, nil
+}
+
+// TryWrite appends s if the result fits within the limit, and otherwise
+// leaves the builder unchanged. It reports whether s was written.
+func (b *Builder) TryWrite(s string) bool {
+	if b.limited && b.widthWith(s) > b.limit {
+		return false
+	}
+	b.WriteString(s)
+	return true
+}
+
+// WriteTruncated appends s, or if the result would not fit within the limit,
+// the longest part of s that fits with ellipsis after it, cut at a cluster
+// boundary. If even the ellipsis does not fit, s is cut without it. It
+// reports whether s was written whole.
+//
+// Example:
+//
+//	b := uniwidth.NewBuilder(8)
+//	b.WriteTruncated("main.go 世界", "…") // false, "main.go…"
+func (b *Builder) WriteTruncated(s, ellipsis string) bool {
+	if b.TryWrite(s) {
+		return true
+	}
+
+	// Column counts at each cluster boundary of s, continuing the clusters
+	// already written. Offset 0 (nothing of s) is always a candidate.
+	t := b.sb.String()[b.tail:] + s
+	cuts := []int{0}
+	for i, col := 0, b.head; i < len(t); {
+		n, w := nextCluster(t[i:], b.o)
+		i += n
+		col += w
+		if i > len(t)-len(s) && i < len(t) && col <= b.limit {
+			cuts = append(cuts, i-(len(t)-len(s)))
+		}
+	}
+
+	for _, suffix := range []string{ellipsis, ""} {
+		for k := len(cuts) - 1; k >= 0; k-- {
+			if head := s[:cuts[k]] + suffix; b.widthWith(head) <= b.limit {
+				b.WriteString(head)
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// widthWith returns the width the string would have with s appended.
+func (b *Builder) widthWith(s string) int {
+	return b.head + stringWidth(b.sb.String()[b.tail:]+s, b.o)
+}
+
+// measure updates the width after a write. It moves tail past the clusters
+// that later writes can no longer change (all but the last, and an
+// incomplete rune at the end) and measures the rest.
+func (b *Builder) measure() {
+	s := b.sb.String()
+	end := completeRunes(s)
+	for b.tail < end {
+		n, w := nextCluster(s[b.tail:end], b.o)
+		if b.tail+n == end {
+			break
+		}
+		b.head += w
+		b.tail += n
+	}
+	b.width = b.head + stringWidth(s[b.tail:], b.o)
+}
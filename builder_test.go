@@ -0,0 +1,180 @@
+package uniwidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBuilder_Width(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []string
+		want     int
+	}{
+		{"empty", nil, 0},
+		{"ASCII", []string{"hello", " ", "world"}, 11},
+		{"CJK", []string{"世界", "你好"}, 8},
+		{"ZWJ across appends", []string{"👨", "\u200d👩", "\u200d👧"}, 2},
+		{"ZWJ alone then emoji", []string{"👨\u200d", "👩"}, 2},
+		{"combining mark across appends", []string{"e", "\u0301", "\u0302"}, 1},
+		{"skin tone across appends", []string{"👍", "🏽"}, 2},
+		{"flag across appends", []string{"🇯", "🇵", "🇺"}, 4},
+		{"keycap across appends", []string{"1", "\uFE0F", "\u20E3"}, 2},
+		{"variation selector across appends", []string{"\u2764", "\uFE0E"}, 1},
+		{"NFD Hangul across appends", []string{"\u1100", "\u1161", "\u11A8"}, 2},
+		{"rune split across appends", []string{"a\xe4", "\xb8", "\x96"}, 3},
+		{"mark split across appends", []string{"e\xcc", "\x81"}, 1},
+		{"invalid UTF-8", []string{"a\xff", "b"}, 3},
+		{"controls", []string{"a\r", "\nb"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Builder
+			for _, s := range tt.segments {
+				b.WriteString(s)
+			}
+			if got := b.Width(); got != tt.want {
+				t.Errorf("Width() = %d, want %d", got, tt.want)
+			}
+			if got, want := b.Width(), StringWidth(b.String()); got != want {
+				t.Errorf("Width() = %d, StringWidth(%q) = %d", got, b.String(), want)
+			}
+		})
+	}
+}
+
+func TestBuilder_WriteMethods(t *testing.T) {
+	var b Builder
+	b.WriteString("a")
+	b.WriteByte(0xe4)
+	b.Write([]byte{0xb8, 0x96})
+	b.WriteRune('👍')
+	b.WriteRune('🏽')
+	fmt.Fprintf(&b, "%d", 42)
+
+	if got, want := b.String(), "a世👍🏽42"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := b.Len(), len("a世👍🏽42"); got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+	if got := b.Width(); got != 7 {
+		t.Errorf("Width() = %d, want 7", got)
+	}
+	if got := b.Remaining(); got != maxInt {
+		t.Errorf("Remaining() without limit = %d, want %d", got, maxInt)
+	}
+
+	b.Reset()
+	if b.String() != "" || b.Width() != 0 {
+		t.Errorf("after Reset: String, Width = %q, %d, want \"\", 0", b.String(), b.Width())
+	}
+}
+
+func TestBuilder_Options(t *testing.T) {
+	b := NewBuilder(4, WithEastAsianAmbiguous(EAWide), WithControlPolicy(ControlCaret))
+	b.WriteString("±")
+	if got := b.Width(); got != 2 {
+		t.Errorf("Width() = %d, want 2", got)
+	}
+	if b.TryWrite("\x01x") {
+		t.Errorf("TryWrite(\"\\x01x\") = true, want false (^A is 2 columns)")
+	}
+
+	b.Reset()
+	if got := b.Remaining(); got != 4 {
+		t.Errorf("Remaining() after Reset = %d, want 4 (limit kept)", got)
+	}
+	b.WriteString("±±")
+	if got := b.Remaining(); got != 0 {
+		t.Errorf("Remaining() = %d, want 0 (options kept)", got)
+	}
+}
+
+func TestBuilder_TryWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		prefix  string
+		s       string
+		ok      bool
+		want    string
+		remains int
+	}{
+		{"fits", 10, "ab", "cdef", true, "abcdef", 4},
+		{"fits exactly", 6, "ab", "cdef", true, "abcdef", 0},
+		{"overflows", 5, "ab", "cdef", false, "ab", 3},
+		{"wide overflows", 5, "ab", "世界", false, "ab", 3},
+		{"joining emoji fits", 2, "👨", "\u200d👩", true, "👨\u200d👩", 0},
+		{"combining mark fits when full", 1, "e", "\u0301", true, "e\u0301", 0},
+		{"zero limit", 0, "", "a", false, "", 0},
+		{"zero-width at zero limit", 0, "", "\u200b", true, "\u200b", 0},
+		{"negative limit", -3, "", "a", false, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder(tt.limit)
+			b.WriteString(tt.prefix)
+			if ok := b.TryWrite(tt.s); ok != tt.ok {
+				t.Errorf("TryWrite(%q) = %v, want %v", tt.s, ok, tt.ok)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if got := b.Remaining(); got != tt.remains {
+				t.Errorf("Remaining() = %d, want %d", got, tt.remains)
+			}
+		})
+	}
+}
+
+func TestBuilder_WriteTruncated(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		prefix   string
+		s        string
+		ellipsis string
+		ok       bool
+		want     string
+	}{
+		{"fits", 10, "ab", "cdef", "…", true, "abcdef"},
+		{"truncated", 5, "ab", "cdef", "…", false, "abcd…"},
+		{"truncated at wide", 5, "ab", "世界", "…", false, "ab世…"},
+		{"wide does not fit before ellipsis", 4, "ab", "世界", "…", false, "ab…"},
+		{"only ellipsis fits", 3, "ab", "cdef", "…", false, "ab…"},
+		{"nothing fits", 2, "ab", "cdef", "…", false, "ab"},
+		{"ellipsis too wide", 3, "ab", "cdef", "...", false, "abc"},
+		{"no ellipsis", 4, "ab", "cdef", "", false, "abcd"},
+		{"keeps clusters whole", 4, "ab", "e\u0301e\u0301e\u0301", "…", false, "abe\u0301…"},
+		{"does not split emoji sequence", 4, "ab", "👨\u200d👩\u200d👧x", "…", false, "ab…"},
+		{"continues last cluster", 3, "👨", "\u200d👩\u200d👧xyz", "…", false, "👨\u200d👩\u200d👧…"},
+		{"help text", 20, "✅ build ", "passed in 1.2s", "…", false, "✅ build passed in …"},
+		{"empty segment", 0, "", "", "…", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder(tt.limit)
+			b.WriteString(tt.prefix)
+			if ok := b.WriteTruncated(tt.s, tt.ellipsis); ok != tt.ok {
+				t.Errorf("WriteTruncated(%q, %q) = %v, want %v", tt.s, tt.ellipsis, ok, tt.ok)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if b.Width() > tt.limit {
+				t.Errorf("Width() = %d, over the limit %d", b.Width(), tt.limit)
+			}
+		})
+	}
+}
+
+func TestBuilder_WriteTruncatedWithoutLimit(t *testing.T) {
+	var b Builder
+	if !b.WriteTruncated("世界", "…") || b.String() != "世界" {
+		t.Errorf("WriteTruncated without limit = %q, want \"世界\" written whole", b.String())
+	}
+}
@@ -0,0 +1,142 @@
+package uniwidth
+
+import (
+	"iter"
+	"unicode/utf8"
+)
+
+// Clusters returns an iterator over the clusters of s and their display widths.
+//
+// A cluster is the unit StringWidth measures: a base character together with
+// everything that renders with it (combining marks, variation selectors,
+// ZWJ-joined emoji, emoji modifiers, flag pairs, keycaps, tag sequences and
+// conjoining Hangul jamo). Without options, the widths of the clusters add up
+// to StringWidth(s); with options, each cluster is measured as
+// StringWidthWithOptions would measure it.
+//
+// Example:
+//
+//	for cluster, width := range uniwidth.Clusters("é👍🏽世") {
+//	    fmt.Printf("%q %d\n", cluster, width)
+//	}
+//	// "é" 1
+//	// "👍🏽" 2
+//	// "世" 2
+//
+// Invalid UTF-8 bytes are yielded as clusters of their own.
+func Clusters(s string, opts ...Option) iter.Seq2[string, int] {
+	o := clusterOptions(opts)
+	return func(yield func(string, int) bool) {
+		for rest := s; len(rest) > 0; {
+			n, width := nextCluster(rest, o)
+			if !yield(rest[:n], width) {
+				return
+			}
+			rest = rest[n:]
+		}
+	}
+}
+
+// NextBoundary returns the cluster boundary after byte offset i of s and the
+// width of the cluster crossed to reach it, for moving a cursor or deleting
+// forward by user-perceived character. If i is inside a cluster, the
+// boundary is the end of that cluster. At the end of s (or past it), it
+// returns len(s) and 0.
+//
+// Example:
+//
+//	s := "a👍🏽b"
+//	next, width := uniwidth.NextBoundary(s, 1) // 9, 2 (over 👍🏽)
+//
+// Options are applied as in Clusters.
+func NextBoundary(s string, i int, opts ...Option) (next, width int) {
+	if i >= len(s) {
+		return len(s), 0
+	}
+	_, next, width = clusterAt(s, max(i, 0), clusterOptions(opts))
+	return next, width
+}
+
+// PrevBoundary returns the cluster boundary before byte offset i of s and the
+// width of the cluster crossed to reach it, for moving a cursor or deleting
+// backward by user-perceived character. If i is inside a cluster, the
+// boundary is the start of that cluster. At the start of s (or before it),
+// it returns 0 and 0.
+//
+// Example:
+//
+//	s := "a🇯🇵🇺🇸"
+//	prev, width := uniwidth.PrevBoundary(s, len(s)) // 9, 2 (over 🇺🇸)
+//
+// Clusters are found as StringWidth finds them scanning forward: the scan
+// restarts at the nearest preceding rune that starts a cluster whatever
+// precedes it, so ZWJ sequences, regional indicator pairs, emoji modifiers
+// and combining marks are crossed whole. Options are applied as in Clusters.
+func PrevBoundary(s string, i int, opts ...Option) (prev, width int) {
+	if i <= 0 {
+		return 0, 0
+	}
+	prev, _, width = clusterAt(s, min(i, len(s))-1, clusterOptions(opts))
+	return prev, width
+}
+
+// clusterOptions returns the options for scanning clusters: nil without
+// options, so that clusters are measured exactly like StringWidth.
+func clusterOptions(opts []Option) *Options {
+	if len(opts) == 0 {
+		return nil
+	}
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &options
+}
+
+// clusterAt returns the start, end and width of the cluster of s containing
+// byte i, which must be less than len(s).
+func clusterAt(s string, i int, o *Options) (start, end, width int) {
+	start = i
+	for start > 0 && !startsCluster(s, start, o) {
+		start--
+	}
+
+	for {
+		n, w := nextCluster(s[start:], o)
+		if start+n > i {
+			return start, start + n, w
+		}
+		start += n
+	}
+}
+
+// startsCluster reports whether the rune at s[i] starts a cluster whatever
+// precedes it, so that scanning forward from i finds the same clusters as
+// scanning from the start of s. This holds for controls (except LF after CR)
+// and for runes with a width that nothing extends with: not regional
+// indicators (which pair from the start of their run), emoji modifiers,
+// Hangul jamo and syllables, or Extended_Pictographic runes after a ZWJ.
+func startsCluster(s string, i int, o *Options) bool {
+	if i == 0 {
+		return true
+	}
+	// A continuation byte may be part of the rune before it; leave it to
+	// the forward scan.
+	if !utf8.RuneStart(s[i]) {
+		return false
+	}
+
+	r, _ := decodeRune(s[i:])
+	prev, _ := utf8.DecodeLastRuneInString(s[:i])
+	switch {
+	case r == '\n':
+		return prev != '\r'
+	case isControl(r):
+		return true
+	case isRegionalIndicator(r), isEmojiModifier(r), hangulSyllableType(r) != hangulNone:
+		return false
+	case isExtendedPictographic(r) && prev == 0x200D:
+		return false
+	}
+	return o.runeWidth(r) > 0
+}
@@ -0,0 +1,236 @@
+package uniwidth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClusters(t *testing.T) {
+	type cluster struct {
+		s     string
+		width int
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		opts []Option
+		want []cluster
+	}{
+		{"empty", "", nil, nil},
+		{"ASCII", "ab", nil, []cluster{{"a", 1}, {"b", 1}}},
+		{"combining mark", "e\u0301x", nil, []cluster{{"e\u0301", 1}, {"x", 1}}},
+		{"CJK", "世界", nil, []cluster{{"世", 2}, {"界", 2}}},
+		{"ZWJ family", "👨‍👩‍👧!", nil, []cluster{{"👨‍👩‍👧", 2}, {"!", 1}}},
+		{"skin tone", "👍🏽", nil, []cluster{{"👍🏽", 2}}},
+		{"flags", "🇺🇸🇫", nil, []cluster{{"🇺🇸", 2}, {"🇫", 2}}},
+		{"keycap", "1\uFE0F\u20E32", nil, []cluster{{"1\uFE0F\u20E3", 2}, {"2", 1}}},
+		{"text presentation", "\u2764\uFE0E", nil, []cluster{{"\u2764\uFE0E", 1}}},
+		{"selector after CJK", "\u4E16\uFE0Ea", nil, []cluster{{"\u4E16\uFE0E", 2}, {"a", 1}}},
+		{"NFD Hangul", "\u1100\u1161\u11A8a", nil, []cluster{{"\u1100\u1161\u11A8", 2}, {"a", 1}}},
+		{"CR LF", "a\r\nb", nil, []cluster{{"a", 1}, {"\r\n", 0}, {"b", 1}}},
+		{"control breaks sequence", "e\n\u0301", nil, []cluster{{"e", 1}, {"\n", 0}, {"\u0301", 0}}},
+		{"invalid UTF-8", "a\xffb", nil, []cluster{{"a", 1}, {"\xff", 1}, {"b", 1}}},
+		{"ambiguous narrow", "±", nil, []cluster{{"±", 1}}},
+		{"ambiguous wide", "±a", []Option{WithEastAsianAmbiguous(EAWide)}, []cluster{{"±", 2}, {"a", 1}}},
+		{"lone RI narrow", "🇺🇸🇫", []Option{WithLoneRegionalIndicatorWidth(1)}, []cluster{{"🇺🇸", 2}, {"🇫", 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []cluster
+			for s, width := range Clusters(tt.s, tt.opts...) {
+				got = append(got, cluster{s, width})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Clusters(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestClusters_SumMatchesStringWidth verifies that the cluster widths add up
+// to StringWidth and that the clusters cover the input exactly.
+func TestClusters_SumMatchesStringWidth(t *testing.T) {
+	inputs := []string{
+		"Hello, 世界!",
+		"👨‍👩‍👧‍👦 family, 👍🏽 thumbs, 🇯🇵 flag",
+		"1️⃣ #⃣ 🏴󠁧󠁢󠁳󠁣󠁴󠁿",
+		"café naïve é",
+		"각 한국어",
+		"tab\there\r\nnext\x1b[0m",
+		"❤️ ❤︎ ☺",
+		"a\xff\xfe世",
+	}
+
+	for _, s := range inputs {
+		sum, joined := 0, ""
+		for cluster, width := range Clusters(s) {
+			sum += width
+			joined += cluster
+		}
+		if want := StringWidth(s); sum != want {
+			t.Errorf("sum of Clusters(%q) widths = %d, StringWidth = %d", s, sum, want)
+		}
+		if joined != s {
+			t.Errorf("Clusters(%q) joined = %q", s, joined)
+		}
+	}
+}
+
+func TestClusters_EarlyBreak(t *testing.T) {
+	seq := Clusters("世界你好")
+
+	// The iterator can be reused and stops when the loop breaks.
+	for range 2 {
+		n := 0
+		for range seq {
+			n++
+			if n == 2 {
+				break
+			}
+		}
+		if n != 2 {
+			t.Errorf("iterations before break = %d, want 2", n)
+		}
+	}
+}
+
+func TestNextBoundary(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		i     int
+		next  int
+		width int
+	}{
+		{"empty", "", 0, 0, 0},
+		{"ASCII", "ab", 0, 1, 1},
+		{"end", "ab", 2, 2, 0},
+		{"past end", "ab", 5, 2, 0},
+		{"negative", "ab", -1, 1, 1},
+		{"combining mark", "e\u0301x", 0, 3, 1},
+		{"inside cluster", "e\u0301x", 1, 3, 1},
+		{"CJK", "a世", 1, 4, 2},
+		{"ZWJ family", "👨‍👩‍👧!", 0, 18, 2},
+		{"skin tone", "a👍🏽b", 1, 9, 2},
+		{"second flag", "🇺🇸🇫🇷", 8, 16, 2},
+		{"inside flag pair", "🇺🇸🇫🇷", 12, 16, 2},
+		{"keycap", "1\uFE0F\u20E3", 0, 7, 2},
+		{"NFD Hangul", "\u1100\u1161\u11A8a", 3, 9, 2},
+		{"CR LF", "a\r\nb", 1, 3, 0},
+		{"LF of CR LF", "a\r\nb", 2, 3, 0},
+		{"invalid UTF-8", "a\xffb", 1, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, width := NextBoundary(tt.s, tt.i)
+			if next != tt.next || width != tt.width {
+				t.Errorf("NextBoundary(%q, %d) = %d, %d, want %d, %d", tt.s, tt.i, next, width, tt.next, tt.width)
+			}
+		})
+	}
+}
+
+func TestPrevBoundary(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		i     int
+		prev  int
+		width int
+	}{
+		{"empty", "", 0, 0, 0},
+		{"ASCII", "ab", 2, 1, 1},
+		{"start", "ab", 0, 0, 0},
+		{"negative", "ab", -1, 0, 0},
+		{"past end", "ab", 5, 1, 1},
+		{"combining marks", "xe\u0301\u0302", 6, 1, 1},
+		{"inside cluster", "xe\u0301\u0302", 4, 1, 1},
+		{"CJK", "a世", 4, 1, 2},
+		{"ZWJ family", "!👨‍👩‍👧", 19, 1, 2},
+		{"ZWJ to copyright", "👨‍©", 9, 0, 2},
+		{"skin tone", "a👍🏽", 9, 1, 2},
+		{"modifier after marks", "👍\u0301🏽", 10, 0, 2},
+		{"lone modifier", "a🏽", 5, 1, 2},
+		{"flags", "a🇯🇵🇺🇸", 17, 9, 2},
+		{"odd regional indicator run", "🇯🇵🇺🇸🇫", 20, 16, 2},
+		{"odd run, before last", "🇯🇵🇺🇸🇫", 16, 8, 2},
+		{"keycap", "x1\uFE0F\u20E3", 8, 1, 2},
+		{"text presentation", "x❤\uFE0E", 7, 1, 1},
+		{"NFD Hangul", "a\u1100\u1161\u11A8", 10, 1, 2},
+		{"syllable and jamo", "a\uAC00\u11A8", 7, 1, 2},
+		{"tag sequence", "a🏴\U000E0067\U000E0062\U000E007F", 17, 1, 2},
+		{"CR LF", "a\r\n", 3, 1, 0},
+		{"control breaks sequence", "e\n\u0301", 4, 2, 0},
+		{"invalid UTF-8", "a\xff", 2, 1, 1},
+		{"truncated rune", "a\xe4\xb8", 3, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev, width := PrevBoundary(tt.s, tt.i)
+			if prev != tt.prev || width != tt.width {
+				t.Errorf("PrevBoundary(%q, %d) = %d, %d, want %d, %d", tt.s, tt.i, prev, width, tt.prev, tt.width)
+			}
+		})
+	}
+}
+
+func TestBoundaries_Options(t *testing.T) {
+	opts := []Option{WithEastAsianAmbiguous(EAWide), WithControlPolicy(ControlCaret)}
+	if next, width := NextBoundary("±\x01", 0, opts...); next != 2 || width != 2 {
+		t.Errorf("NextBoundary(\"±\\x01\", 0) = %d, %d, want 2, 2", next, width)
+	}
+	if prev, width := PrevBoundary("±\x01", 3, opts...); prev != 2 || width != 2 {
+		t.Errorf("PrevBoundary(\"±\\x01\", 3) = %d, %d, want 2, 2", prev, width)
+	}
+}
+
+// checkBoundaries reports an error if stepping through s with NextBoundary
+// or PrevBoundary, from any offset, disagrees with Clusters.
+func checkBoundaries(t *testing.T, s string) {
+	t.Helper()
+
+	// The cluster start and end around each byte, from a forward scan.
+	starts := make([]int, len(s))
+	ends := make([]int, len(s))
+	widths := make([]int, len(s))
+	i := 0
+	for cluster, width := range Clusters(s) {
+		for j := i; j < i+len(cluster); j++ {
+			starts[j], ends[j], widths[j] = i, i+len(cluster), width
+		}
+		i += len(cluster)
+	}
+
+	for i := range len(s) {
+		if next, width := NextBoundary(s, i); next != ends[i] || width != widths[i] {
+			t.Errorf("NextBoundary(%q, %d) = %d, %d, want %d, %d", s, i, next, width, ends[i], widths[i])
+		}
+		if prev, width := PrevBoundary(s, i+1); prev != starts[i] || width != widths[i] {
+			t.Errorf("PrevBoundary(%q, %d) = %d, %d, want %d, %d", s, i+1, prev, width, starts[i], widths[i])
+		}
+	}
+}
+
+func TestBoundaries_MatchClusters(t *testing.T) {
+	inputs := []string{
+		"Hello, 世界!",
+		"👨‍👩‍👧‍👦 family, 👍🏽 thumbs, 🇯🇵 flag",
+		"🇯🇵🇺🇸🇫🇷🇩🇪🇫",
+		"1\uFE0F\u20E3 #\u20E3 🏴󠁧󠁢󠁳󠁣󠁴󠁿",
+		"café naïve é\u0301\u0302",
+		"각 한국어 \u1100\u1161\u11A8\uAC00\u11A8",
+		"tab\there\r\nnext\x1b[0m\r\r\n",
+		"❤\uFE0F ❤\uFE0E ☺ 👩‍©‍",
+		"a\xff\xfe世\xe4\xb8\x80\x80",
+		"\u0301\u0302x",
+		"👍\u0301🏽🏽",
+	}
+
+	for _, s := range inputs {
+		checkBoundaries(t, s)
+	}
+}
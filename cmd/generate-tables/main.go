@@ -3,15 +3,32 @@
 // This tool downloads and parses:
 // - EastAsianWidth.txt - East Asian Width property assignments
 // - emoji-data.txt - Emoji presentation properties
+// - emoji-variation-sequences.txt - Bases of emoji variation sequences
+// - UnicodeData.txt - General categories (for the POSIX wcwidth table)
+// - PropList.txt - Prepended_Concatenation_Mark (for the POSIX wcwidth table)
 //
 // It generates optimized tables for uniwidth's tiered lookup strategy:
 //   - Tier 1-3 (hot paths) are hardcoded in uniwidth.go for O(1) lookup
 //   - This generates Tier 4 tables: both legacy binary search tables and
 //     a 3-stage multi-stage lookup table for O(1) fallback
+//   - A second 3-stage table reproduces glibc's wcwidth() for Wcwidth
+//   - A 3-stage byte table holds the emoji-data.txt properties (Emoji,
+//     Emoji_Presentation, Extended_Pictographic, Emoji_Modifier,
+//     Emoji_Modifier_Base, Emoji_Component), plus a bit for the bases of
+//     emoji-variation-sequences.txt
+//   - A 3-stage byte table holds the East_Asian_Width class (N, A, H, W, F,
+//     Na) of every codepoint for EastAsianWidthOf
+//
+// The tests verify the emoji property and East Asian Width tables against
+// trimmed copies of the data files in testdata/, written by
+// scripts/trim-ucd.sh and parsed by the tests themselves.
 //
 // Usage:
 //
-//	go run cmd/generate-tables/main.go
+//	go run cmd/generate-tables/main.go [-data dir]
+//
+// With -data, the files are read from a local directory laid out like
+// https://www.unicode.org/Public/16.0.0/ucd/ instead of being downloaded.
 //
 // Output:
 //
@@ -20,11 +37,13 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
@@ -33,8 +52,12 @@ import (
 
 const (
 	unicodeVersion    = "16.0.0"
-	eastAsianWidthURL = "https://www.unicode.org/Public/16.0.0/ucd/EastAsianWidth.txt"
-	emojiDataURL      = "https://www.unicode.org/Public/16.0.0/ucd/emoji/emoji-data.txt"
+	ucdBaseURL        = "https://www.unicode.org/Public/16.0.0/ucd/"
+	eastAsianWidthURL = ucdBaseURL + "EastAsianWidth.txt"
+	emojiDataURL      = ucdBaseURL + "emoji/emoji-data.txt"
+	emojiVariationURL = ucdBaseURL + "emoji/emoji-variation-sequences.txt"
+	unicodeDataURL    = ucdBaseURL + "UnicodeData.txt"
+	propListURL       = ucdBaseURL + "PropList.txt"
 	outputFile        = "tables_generated.go"
 
 	// maxCodepoint is the maximum valid Unicode codepoint (U+10FFFF).
@@ -45,8 +68,79 @@ const (
 	widthNarrow    = 1 // width 1: narrow (default)
 	widthWide      = 2 // width 2: wide (CJK, emoji, fullwidth)
 	widthAmbiguous = 3 // width 1 in neutral context, 2 in East Asian
+
+	// 2-bit encoding for the wcwidth table leaves (glibc semantics).
+	wcwidthZero        = 0 // wcwidth() == 0
+	wcwidthOne         = 1 // wcwidth() == 1
+	wcwidthTwo         = 2 // wcwidth() == 2
+	wcwidthNonPrinting = 3 // wcwidth() == -1
+
+	// Property bits of the emoji property table (one byte per codepoint).
+	propEmoji                = 1 << 0 // Emoji
+	propEmojiPresentation    = 1 << 1 // Emoji_Presentation
+	propExtendedPictographic = 1 << 2 // Extended_Pictographic
+	propEmojiModifier        = 1 << 3 // Emoji_Modifier
+	propEmojiModifierBase    = 1 << 4 // Emoji_Modifier_Base
+	propEmojiComponent       = 1 << 5 // Emoji_Component
+	propEmojiVariationBase   = 1 << 6 // base of an emoji variation sequence
+
+	// East_Asian_Width classes of the East Asian Width table (one byte per
+	// codepoint). Must match the EAProperty constants in uniwidth.
+	eawNeutral   = 0 // N (also the default for unlisted codepoints)
+	eawAmbiguous = 1 // A
+	eawHalfwidth = 2 // H
+	eawWide      = 3 // W
+	eawFullwidth = 4 // F
+	eawNarrow    = 5 // Na
 )
 
+// emojiProperties lists the emoji-data.txt properties stored in the emoji
+// property table, with their bits.
+var emojiProperties = []struct {
+	property string
+	bit      byte
+}{
+	{"Emoji", propEmoji},
+	{"Emoji_Presentation", propEmojiPresentation},
+	{"Extended_Pictographic", propExtendedPictographic},
+	{"Emoji_Modifier", propEmojiModifier},
+	{"Emoji_Modifier_Base", propEmojiModifierBase},
+	{"Emoji_Component", propEmojiComponent},
+}
+
+// eastAsianWidthClasses lists the East_Asian_Width values stored in the East
+// Asian Width table, with their encodings.
+var eastAsianWidthClasses = []struct {
+	class string
+	value byte
+}{
+	{"N", eawNeutral},
+	{"A", eawAmbiguous},
+	{"H", eawHalfwidth},
+	{"W", eawWide},
+	{"F", eawFullwidth},
+	{"Na", eawNarrow},
+}
+
+// dataDir, when set, is a local copy of the UCD used instead of downloading.
+var dataDir = flag.String("data", "", "read UCD files from this directory instead of unicode.org")
+
+// multiStageTable is a deduplicated 3-stage lookup table of 2-bit values.
+type multiStageTable struct {
+	root   [256]byte
+	middle [][64]byte
+	leaves [][32]byte
+}
+
+// propertyTable is a deduplicated 3-stage lookup table of 8-bit property
+// flags: the same root and middle layout as multiStageTable, with one byte
+// per codepoint in the leaves.
+type propertyTable struct {
+	root   [256]byte
+	middle [][64]byte
+	leaves [][128]byte
+}
+
 // runeRange represents a contiguous range of runes with the same property.
 type runeRange struct {
 	first rune
@@ -64,11 +158,13 @@ const (
 )
 
 func main() {
+	flag.Parse()
+
 	log.Println("Generating Unicode 16.0 width tables...")
 
 	// Download and parse Unicode data
 	log.Println("Downloading EastAsianWidth.txt...")
-	eawData, err := downloadFile(eastAsianWidthURL)
+	eawData, err := loadFile(eastAsianWidthURL)
 	if err != nil {
 		log.Fatalf("Failed to download EastAsianWidth.txt: %v", err)
 	}
@@ -77,7 +173,7 @@ func main() {
 	wideRanges, ambiguousRanges := parseEastAsianWidth(eawData)
 
 	log.Println("Downloading emoji-data.txt...")
-	emojiData, err := downloadFile(emojiDataURL)
+	emojiData, err := loadFile(emojiDataURL)
 	if err != nil {
 		log.Fatalf("Failed to download emoji-data.txt: %v", err)
 	}
@@ -85,14 +181,45 @@ func main() {
 	log.Println("Parsing Emoji data...")
 	emojiRanges := parseEmojiData(emojiData)
 
+	log.Println("Downloading emoji-variation-sequences.txt...")
+	variationData, err := loadFile(emojiVariationURL)
+	if err != nil {
+		log.Fatalf("Failed to download emoji-variation-sequences.txt: %v", err)
+	}
+	variationBases := parseVariationBases(variationData)
+
+	log.Println("Downloading UnicodeData.txt...")
+	unicodeData, err := loadFile(unicodeDataURL)
+	if err != nil {
+		log.Fatalf("Failed to download UnicodeData.txt: %v", err)
+	}
+
+	log.Println("Downloading PropList.txt...")
+	propList, err := loadFile(propListURL)
+	if err != nil {
+		log.Fatalf("Failed to download PropList.txt: %v", err)
+	}
+
+	log.Println("Parsing general categories and PropList data...")
+	categories := parseUnicodeData(unicodeData)
+	prependedMarks := parseProperty(propList, "Prepended_Concatenation_Mark")
+
 	// Build multi-stage table from UNFILTERED ranges (covers all codepoints)
 	log.Println("Building multi-stage lookup table...")
-	root, middle, leaves := buildMultiStageTable(wideRanges, ambiguousRanges, emojiRanges)
-	log.Printf("  - Root table: %d entries", len(root))
-	log.Printf("  - Middle tables: %d unique sub-tables", len(middle))
-	log.Printf("  - Leaf tables: %d unique sub-tables", len(leaves))
-	totalBytes := len(root) + len(middle)*64 + len(leaves)*32
-	log.Printf("  - Total size: %d bytes (%.1f KiB)", totalBytes, float64(totalBytes)/1024)
+	width := buildMultiStageTable(buildWidthMap(wideRanges, ambiguousRanges, emojiRanges))
+	logTableSize(width)
+
+	log.Println("Building wcwidth lookup table...")
+	wcwidth := buildMultiStageTable(buildWcwidthMap(categories, wideRanges, prependedMarks))
+	logTableSize(wcwidth)
+
+	log.Println("Building emoji property table...")
+	emojiProps := buildPropertyTable(buildEmojiPropertyMap(emojiData, variationBases))
+	logPropertyTableSize(emojiProps)
+
+	log.Println("Building East Asian Width table...")
+	eastAsianWidth := buildPropertyTable(buildEastAsianWidthMap(eawData))
+	logPropertyTableSize(eastAsianWidth)
 
 	// Merge emoji into wide ranges for legacy tables
 	wideRanges = mergeRanges(wideRanges, emojiRanges)
@@ -115,7 +242,7 @@ func main() {
 
 	// Generate output file
 	log.Println("Generating tables_generated.go...")
-	err = generateGoFile(wideRanges, zeroWidthRanges, ambiguousRanges, &root, middle, leaves)
+	err = generateGoFile(wideRanges, zeroWidthRanges, ambiguousRanges, &width, &wcwidth, &emojiProps, &eastAsianWidth)
 	if err != nil {
 		log.Fatalf("Failed to generate Go file: %v", err)
 	}
@@ -124,10 +251,44 @@ func main() {
 	log.Printf("  - Wide characters: %d ranges", len(wideRanges))
 	log.Printf("  - Zero-width characters: %d ranges", len(zeroWidthRanges))
 	log.Printf("  - Ambiguous characters: %d ranges", len(ambiguousRanges))
-	log.Printf("  - Multi-stage table: root=%d, middle=%d, leaves=%d", len(root), len(middle), len(leaves))
+	log.Printf("  - Multi-stage table: root=%d, middle=%d, leaves=%d", len(width.root), len(width.middle), len(width.leaves))
+	log.Printf("  - Wcwidth table: root=%d, middle=%d, leaves=%d", len(wcwidth.root), len(wcwidth.middle), len(wcwidth.leaves))
+	log.Printf("  - Emoji property table: root=%d, middle=%d, leaves=%d", len(emojiProps.root), len(emojiProps.middle), len(emojiProps.leaves))
+	log.Printf("  - East Asian Width table: root=%d, middle=%d, leaves=%d", len(eastAsianWidth.root), len(eastAsianWidth.middle), len(eastAsianWidth.leaves))
 	log.Println("Done!")
 }
 
+// logTableSize logs the dimensions and total size of a multi-stage table.
+func logTableSize(t multiStageTable) {
+	log.Printf("  - Root table: %d entries", len(t.root))
+	log.Printf("  - Middle tables: %d unique sub-tables", len(t.middle))
+	log.Printf("  - Leaf tables: %d unique sub-tables", len(t.leaves))
+	totalBytes := len(t.root) + len(t.middle)*64 + len(t.leaves)*32
+	log.Printf("  - Total size: %d bytes (%.1f KiB)", totalBytes, float64(totalBytes)/1024)
+}
+
+// logPropertyTableSize logs the dimensions and total size of a property table.
+func logPropertyTableSize(t propertyTable) {
+	log.Printf("  - Middle tables: %d unique sub-tables", len(t.middle))
+	log.Printf("  - Leaf tables: %d unique sub-tables", len(t.leaves))
+	totalBytes := len(t.root) + len(t.middle)*64 + len(t.leaves)*128
+	log.Printf("  - Total size: %d bytes (%.1f KiB)", totalBytes, float64(totalBytes)/1024)
+}
+
+// loadFile returns the content of a UCD file, reading it from -data when set
+// and downloading it otherwise.
+func loadFile(url string) (string, error) {
+	if *dataDir == "" {
+		return downloadFile(url)
+	}
+	name := filepath.Join(*dataDir, filepath.FromSlash(strings.TrimPrefix(url, ucdBaseURL)))
+	data, err := os.ReadFile(name) //nolint:gosec // G304: path comes from the -data flag
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // downloadFile downloads a file from a URL and returns its content as a string.
 //
 //nolint:gosec // URL is hardcoded constant from Unicode.org
@@ -247,6 +408,110 @@ func parseEmojiData(data string) []runeRange {
 	return ranges
 }
 
+// parseUnicodeData parses UnicodeData.txt and returns the General_Category
+// of every codepoint. Unassigned codepoints are reported as "Cn".
+func parseUnicodeData(data string) []string {
+	categories := make([]string, maxCodepoint+1)
+	for i := range categories {
+		categories[i] = "Cn"
+	}
+
+	// Fields: code;name;general_category;... Large blocks are given as a
+	// "<..., First>" line followed by a "<..., Last>" line.
+	rangeStart := rune(-1)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ";")
+		if len(fields) < 3 {
+			continue
+		}
+
+		cp, err := strconv.ParseInt(fields[0], 16, 64)
+		if err != nil || cp > maxCodepoint {
+			continue
+		}
+
+		name, gc := fields[1], fields[2]
+		switch {
+		case strings.HasSuffix(name, ", First>"):
+			rangeStart = rune(cp)
+		case strings.HasSuffix(name, ", Last>") && rangeStart >= 0:
+			for c := rangeStart; c <= rune(cp); c++ {
+				categories[c] = gc
+			}
+			rangeStart = -1
+		default:
+			categories[cp] = gc
+		}
+	}
+
+	return categories
+}
+
+// parseProperty parses a UCD property file (PropList.txt, emoji-data.txt)
+// and returns the ranges that have the named binary property.
+func parseProperty(data, property string) []runeRange {
+	// Regex to match lines like:
+	// 0600..0605    ; Prepended_Concatenation_Mark # Cf   [6] ARABIC NUMBER SIGN..ARABIC NUMBER MARK ABOVE
+	lineRe := regexp.MustCompile(`^([0-9A-F]+)(?:\.\.([0-9A-F]+))?\s*;\s*` + regexp.QuoteMeta(property) + `\s*(?:#|$)`)
+
+	var ranges []runeRange
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		matches := lineRe.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if matches == nil {
+			continue
+		}
+
+		first, err := strconv.ParseInt(matches[1], 16, 64)
+		if err != nil {
+			continue
+		}
+
+		last := first
+		if matches[2] != "" {
+			l, err := strconv.ParseInt(matches[2], 16, 64)
+			if err != nil {
+				continue
+			}
+			last = l
+		}
+
+		ranges = append(ranges, runeRange{first: rune(first), last: rune(last)})
+	}
+
+	return ranges
+}
+
+// parseVariationBases parses emoji-variation-sequences.txt and returns the
+// base characters of its sequences. Every base is listed twice, with U+FE0E
+// (text style) and U+FE0F (emoji style):
+//
+//	00A9 FE0E ; text style;  # COPYRIGHT SIGN
+//	00A9 FE0F ; emoji style; # COPYRIGHT SIGN
+func parseVariationBases(data string) []runeRange {
+	lineRe := regexp.MustCompile(`^([0-9A-F]+)\s+FE0[EF]\s*;`)
+
+	var bases []runeRange
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		matches := lineRe.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if matches == nil {
+			continue
+		}
+
+		cp, err := strconv.ParseInt(matches[1], 16, 64)
+		if err != nil {
+			continue
+		}
+		bases = append(bases, runeRange{first: rune(cp), last: rune(cp)})
+	}
+
+	return optimizeRanges(bases)
+}
+
 // generateZeroWidthRanges generates ranges for zero-width characters.
 func generateZeroWidthRanges() []runeRange {
 	// These are well-known zero-width character ranges
@@ -283,6 +548,8 @@ func generateZeroWidthRanges() []runeRange {
 		{0x094D, 0x094D},
 		{0x0951, 0x0957},
 		{0x0962, 0x0963},
+		// Hangul Jamo medial vowels and final consonants (conjoin with a leading consonant)
+		{0x1160, 0x11FF},
 		// Combining Diacritical Marks Extended (U+1AB0-U+1AFF)
 		{0x1AB0, 0x1AFF},
 		// Combining Diacritical Marks Supplement (U+1DC0-U+1DFF)
@@ -293,10 +560,15 @@ func generateZeroWidthRanges() []runeRange {
 		{0x20D0, 0x20FF},
 		// Variation selectors
 		{0xFE00, 0xFE0F},
+		// Hangul Jamo Extended-B medial vowels and final consonants
+		{0xD7B0, 0xD7FF},
 		// Arabic presentation forms
 		{0xFE20, 0xFE2F},
 		// BOM and other specials
 		{0xFEFF, 0xFEFF},
+		// Tag characters (emoji tag sequences: LANGUAGE TAG, tag specs, CANCEL TAG)
+		{0xE0001, 0xE0001},
+		{0xE0020, 0xE007F},
 		// Emoji variation selectors
 		{0xE0100, 0xE01EF},
 	}
@@ -497,7 +769,72 @@ func buildWidthMap(wide, ambiguous, emoji []runeRange) []byte {
 	return widthMap
 }
 
-// buildMultiStageTable constructs a 3-stage hierarchical lookup table from Unicode data.
+// buildWcwidthMap builds the POSIX wcwidth() value of every codepoint using
+// the rules of glibc's localedata/unicode-gen/utf8_gen.py, encoded as
+// 0=width 0, 1=width 1, 2=width 2, 3=non-printable (-1).
+//
+// The rules, in order of precedence:
+//   - U+0000 is 0
+//   - Unassigned (Cn), controls (Cc), surrogates (Cs) and line/paragraph
+//     separators (Zl, Zp) are -1
+//   - U+00AD SOFT HYPHEN is 1
+//   - Hangul medial vowels and final consonants (U+1160-U+11FF,
+//     U+D7B0-U+D7FF) are 0
+//   - Prepended_Concatenation_Mark characters are 1
+//   - Nonspacing (Mn) and enclosing (Me) marks and format characters (Cf) are 0
+//   - East Asian Width W and F, plus U+3248-U+324F and U+4DC0-U+4DFF, are 2
+//   - Everything else is 1
+func buildWcwidthMap(categories []string, wide, prependedMarks []runeRange) []byte {
+	widthMap := make([]byte, maxCodepoint+1)
+
+	for cp := range widthMap {
+		switch categories[cp] {
+		case "Cn", "Cc", "Cs", "Zl", "Zp":
+			widthMap[cp] = wcwidthNonPrinting
+		case "Mn", "Me", "Cf":
+			widthMap[cp] = wcwidthZero
+		default:
+			widthMap[cp] = wcwidthOne
+		}
+	}
+
+	// Wide characters only widen printable, spacing characters.
+	extraWide := []runeRange{
+		{0x3248, 0x324F}, // Circled numbers on black squares (EAW A)
+		{0x4DC0, 0x4DFF}, // Yijing Hexagram Symbols (EAW N)
+	}
+	for _, rr := range append(append([]runeRange{}, wide...), extraWide...) {
+		for cp := rr.first; cp <= rr.last; cp++ {
+			if widthMap[cp] == wcwidthOne {
+				widthMap[cp] = wcwidthTwo
+			}
+		}
+	}
+
+	// Hangul Jungseong and Jongseong combine with a preceding Choseong.
+	for _, rr := range []runeRange{{0x1160, 0x11FF}, {0xD7B0, 0xD7FF}} {
+		for cp := rr.first; cp <= rr.last; cp++ {
+			if widthMap[cp] != wcwidthNonPrinting {
+				widthMap[cp] = wcwidthZero
+			}
+		}
+	}
+
+	// Prepended concatenation marks are visible format characters.
+	for _, rr := range prependedMarks {
+		for cp := rr.first; cp <= rr.last; cp++ {
+			widthMap[cp] = wcwidthOne
+		}
+	}
+
+	widthMap[0x00AD] = wcwidthOne
+	widthMap[0x0000] = wcwidthZero
+
+	return widthMap
+}
+
+// buildMultiStageTable constructs a 3-stage hierarchical lookup table from a
+// per-codepoint map of 2-bit values.
 //
 // The 3-stage table splits a 21-bit Unicode codepoint into 3 parts:
 //
@@ -510,8 +847,8 @@ func buildWidthMap(wide, ambiguous, emoji []runeRange) []byte {
 // LEAVES (M x 32 entries): packed 2-bit width values, 4 per byte
 //
 // Deduplication of identical sub-tables is critical for compact size.
-func buildMultiStageTable(wide, ambiguous, emoji []runeRange) (root [256]byte, middle [][64]byte, leaves [][32]byte) {
-	widthMap := buildWidthMap(wide, ambiguous, emoji)
+func buildMultiStageTable(widthMap []byte) multiStageTable {
+	var t multiStageTable
 
 	// Maps for deduplication: serialized sub-table -> index
 	leafIndex := make(map[[32]byte]byte)
@@ -547,12 +884,12 @@ func buildMultiStageTable(wide, ambiguous, emoji []runeRange) (root [256]byte, m
 			// Deduplicate leaf table
 			idx, ok := leafIndex[leafTable]
 			if !ok {
-				if len(leaves) > 255 {
-					log.Fatalf("Too many unique leaf tables (%d > 255), cannot fit in uint8", len(leaves))
+				if len(t.leaves) > 255 {
+					log.Fatalf("Too many unique leaf tables (%d > 255), cannot fit in uint8", len(t.leaves))
 				}
-				idx = byte(len(leaves))
+				idx = byte(len(t.leaves))
 				leafIndex[leafTable] = idx
-				leaves = append(leaves, leafTable)
+				t.leaves = append(t.leaves, leafTable)
 			}
 			midTable[midEntry] = idx
 		}
@@ -560,21 +897,109 @@ func buildMultiStageTable(wide, ambiguous, emoji []runeRange) (root [256]byte, m
 		// Deduplicate middle table
 		idx, ok := midIndex[midTable]
 		if !ok {
-			if len(middle) > 255 {
-				log.Fatalf("Too many unique middle tables (%d > 255), cannot fit in uint8", len(middle))
+			if len(t.middle) > 255 {
+				log.Fatalf("Too many unique middle tables (%d > 255), cannot fit in uint8", len(t.middle))
+			}
+			idx = byte(len(t.middle))
+			midIndex[midTable] = idx
+			t.middle = append(t.middle, midTable)
+		}
+		t.root[rootBlock] = idx
+	}
+
+	return t
+}
+
+// buildEmojiPropertyMap returns the emoji property bits of every codepoint,
+// parsed from emoji-data.txt, with propEmojiVariationBase set for the
+// variation sequence bases.
+func buildEmojiPropertyMap(emojiData string, variationBases []runeRange) []byte {
+	props := make([]byte, maxCodepoint+1)
+
+	for _, p := range emojiProperties {
+		for _, rr := range parseProperty(emojiData, p.property) {
+			for cp := rr.first; cp <= rr.last; cp++ {
+				props[cp] |= p.bit
+			}
+		}
+	}
+	for _, rr := range variationBases {
+		for cp := rr.first; cp <= rr.last; cp++ {
+			props[cp] |= propEmojiVariationBase
+		}
+	}
+
+	return props
+}
+
+// buildEastAsianWidthMap returns the East_Asian_Width class of every
+// codepoint, parsed from EastAsianWidth.txt. Codepoints the file does not
+// list are Neutral.
+func buildEastAsianWidthMap(eawData string) []byte {
+	classes := make([]byte, maxCodepoint+1)
+
+	for _, c := range eastAsianWidthClasses {
+		for _, rr := range parseProperty(eawData, c.class) {
+			for cp := rr.first; cp <= rr.last; cp++ {
+				classes[cp] = c.value
+			}
+		}
+	}
+
+	return classes
+}
+
+// buildPropertyTable builds a 3-stage lookup table from a per-codepoint
+// property map, deduplicating identical leaf and middle sub-tables like
+// buildMultiStageTable.
+func buildPropertyTable(props []byte) propertyTable {
+	var t propertyTable
+
+	leafIndex := make(map[[128]byte]byte)
+	midIndex := make(map[[64]byte]byte)
+
+	for rootBlock := 0; rootBlock < 256; rootBlock++ {
+		var midTable [64]byte
+
+		for midEntry := 0; midEntry < 64; midEntry++ {
+			var leafTable [128]byte
+
+			midBaseCP := rootBlock<<13 + midEntry<<7
+			for i := range leafTable {
+				if cp := midBaseCP + i; cp <= maxCodepoint {
+					leafTable[i] = props[cp]
+				}
+			}
+
+			idx, ok := leafIndex[leafTable]
+			if !ok {
+				if len(t.leaves) > 255 {
+					log.Fatalf("Too many unique leaf tables (%d > 255), cannot fit in uint8", len(t.leaves))
+				}
+				idx = byte(len(t.leaves))
+				leafIndex[leafTable] = idx
+				t.leaves = append(t.leaves, leafTable)
+			}
+			midTable[midEntry] = idx
+		}
+
+		idx, ok := midIndex[midTable]
+		if !ok {
+			if len(t.middle) > 255 {
+				log.Fatalf("Too many unique middle tables (%d > 255), cannot fit in uint8", len(t.middle))
 			}
-			idx = byte(len(middle))
+			idx = byte(len(t.middle))
 			midIndex[midTable] = idx
-			middle = append(middle, midTable)
+			t.middle = append(t.middle, midTable)
 		}
-		root[rootBlock] = idx
+		t.root[rootBlock] = idx
 	}
 
-	return root, middle, leaves
+	return t
 }
 
 // generateGoFile generates the Go source file with both legacy and multi-stage tables.
-func generateGoFile(wide, zeroWidth, ambiguous []runeRange, root *[256]byte, middle [][64]byte, leaves [][32]byte) error {
+func generateGoFile(wide, zeroWidth, ambiguous []runeRange, width, wcwidth *multiStageTable, emojiProps, eastAsianWidth *propertyTable) error {
 	file, err := os.Create(outputFile)
 	if err != nil {
 		return err
@@ -592,6 +1017,9 @@ func generateGoFile(wide, zeroWidth, ambiguous []runeRange, root *[256]byte, mid
 // Generated from Unicode %s data files:
 // - EastAsianWidth.txt
 // - emoji-data.txt
+// - emoji-variation-sequences.txt
+// - UnicodeData.txt
+// - PropList.txt
 //
 // To regenerate:
 //   go generate ./...
@@ -600,9 +1028,11 @@ package uniwidth
 
 // This file contains Unicode width tables for character width lookup.
 //
-// Two table formats are provided:
+// Three table formats are provided:
 // 1. Legacy runeRange tables (used by Options API for ambiguous character handling)
 // 2. Multi-stage lookup tables (used by tableLookupWidth for O(1) fallback)
+// 3. Multi-stage property tables (used by the exported Is* predicates and
+//    EastAsianWidthOf)
 
 `, unicodeVersion); err != nil {
 		return fmt.Errorf("failed to write file header: %w", err)
@@ -659,28 +1089,87 @@ package uniwidth
 	writeComment(w, "  0b11 = ambiguous (width 1 in neutral context; 2 in East Asian)")
 	fmt.Fprint(w, "\n")
 
+	writeMultiStageTable(w, "width", width)
+
+	// Write wcwidth table documentation
+	fmt.Fprint(w, "\n")
+	writeComment(w, "3-Stage wcwidth Lookup Table")
+	writeComment(w, "")
+	writeComment(w, "Same layout as the width table above, holding glibc's wcwidth() values")
+	writeComment(w, "(localedata/unicode-gen/utf8_gen.py rules). Used by Wcwidth and Wcswidth.")
+	writeComment(w, "")
+	writeComment(w, "2-bit wcwidth encoding:")
+	writeComment(w, "  0b00 = 0 (NUL, combining, format, Hangul medial/final jamo)")
+	writeComment(w, "  0b01 = 1")
+	writeComment(w, "  0b10 = 2 (East Asian Wide and Fullwidth)")
+	writeComment(w, "  0b11 = -1 (controls, unassigned, surrogates, line/paragraph separators)")
+	fmt.Fprint(w, "\n")
+	writeMultiStageTable(w, "wcwidth", wcwidth)
+
+	// Write emoji property table documentation
+	fmt.Fprint(w, "\n")
+	writeComment(w, "3-Stage Emoji Property Table")
+	writeComment(w, "")
+	writeComment(w, "Same root and middle layout as the width table above, but each leaf holds")
+	writeComment(w, "one byte of property bits per codepoint (emoji-data.txt):")
+	writeComment(w, "")
+	writeComment(w, "Lookup: emojiPropsLeaves[emojiPropsMiddle[emojiPropsRoot[cp>>13]][cp>>7 & 0x3F]][cp & 0x7F]")
+	writeComment(w, "")
+	writeComment(w, "Property bits:")
+	for _, p := range emojiProperties {
+		writeComment(w, fmt.Sprintf("  0x%02X = %s", p.bit, p.property))
+	}
+	writeComment(w, fmt.Sprintf("  0x%02X = base of an emoji variation sequence (emoji-variation-sequences.txt)", propEmojiVariationBase))
+	fmt.Fprint(w, "\n")
+	writePropertyTable(w, "emojiProps", emojiProps)
+
+	// Write East Asian Width table documentation
+	fmt.Fprint(w, "\n")
+	writeComment(w, "3-Stage East Asian Width Table")
+	writeComment(w, "")
+	writeComment(w, "Same layout as the emoji property table above, but each leaf byte holds the")
+	writeComment(w, "East_Asian_Width class of the codepoint (EastAsianWidth.txt):")
+	writeComment(w, "")
+	writeComment(w, "Lookup: eastAsianWidthLeaves[eastAsianWidthMiddle[eastAsianWidthRoot[cp>>13]][cp>>7 & 0x3F]][cp & 0x7F]")
+	writeComment(w, "")
+	writeComment(w, "Classes:")
+	for _, c := range eastAsianWidthClasses {
+		writeComment(w, fmt.Sprintf("  %d = %s", c.value, c.class))
+	}
+	fmt.Fprint(w, "\n")
+	writePropertyTable(w, "eastAsianWidth", eastAsianWidth)
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+	return nil
+}
+
+// writeMultiStageTable writes the root, middle and leaf arrays of a
+// multi-stage table as <name>Root, <name>Middle and <name>Leaves.
+func writeMultiStageTable(w *bufio.Writer, name string, t *multiStageTable) {
 	// Write root table
-	fmt.Fprintf(w, "// widthRoot maps the top 8 bits of a codepoint (cp >> 13) to a middle table index.\n")
+	fmt.Fprintf(w, "// %sRoot maps the top 8 bits of a codepoint (cp >> 13) to a middle table index.\n", name)
 	fmt.Fprintf(w, "// Size: 256 bytes.\n")
-	fmt.Fprint(w, "var widthRoot = [256]uint8{\n")
+	fmt.Fprintf(w, "var %sRoot = [256]uint8{\n", name)
 	for i := 0; i < 256; i += 16 {
 		fmt.Fprint(w, "\t")
 		for j := 0; j < 16; j++ {
 			if j > 0 {
 				fmt.Fprint(w, " ")
 			}
-			fmt.Fprintf(w, "0x%02X,", root[i+j])
+			fmt.Fprintf(w, "0x%02X,", t.root[i+j])
 		}
 		fmt.Fprint(w, "\n")
 	}
 	fmt.Fprint(w, "}\n\n")
 
 	// Write middle tables
-	fmt.Fprintf(w, "// widthMiddle contains %d unique middle sub-tables.\n", len(middle))
+	fmt.Fprintf(w, "// %sMiddle contains %d unique middle sub-tables.\n", name, len(t.middle))
 	fmt.Fprintf(w, "// Each sub-table has 64 entries mapping bits [12:7] to a leaf table index.\n")
-	fmt.Fprintf(w, "// Size: %d bytes.\n", len(middle)*64)
-	fmt.Fprintf(w, "var widthMiddle = [%d][64]uint8{\n", len(middle))
-	for i, mt := range middle {
+	fmt.Fprintf(w, "// Size: %d bytes.\n", len(t.middle)*64)
+	fmt.Fprintf(w, "var %sMiddle = [%d][64]uint8{\n", name, len(t.middle))
+	for i, mt := range t.middle {
 		fmt.Fprintf(w, "\t// Middle table %d\n", i)
 		fmt.Fprint(w, "\t{\n")
 		for row := 0; row < 64; row += 16 {
@@ -702,11 +1191,11 @@ package uniwidth
 	fmt.Fprint(w, "}\n\n")
 
 	// Write leaf tables
-	fmt.Fprintf(w, "// widthLeaves contains %d unique leaf sub-tables.\n", len(leaves))
+	fmt.Fprintf(w, "// %sLeaves contains %d unique leaf sub-tables.\n", name, len(t.leaves))
 	fmt.Fprintf(w, "// Each sub-table has 32 bytes of packed 2-bit width values (128 codepoints).\n")
-	fmt.Fprintf(w, "// Size: %d bytes.\n", len(leaves)*32)
-	fmt.Fprintf(w, "var widthLeaves = [%d][32]uint8{\n", len(leaves))
-	for i, lt := range leaves {
+	fmt.Fprintf(w, "// Size: %d bytes.\n", len(t.leaves)*32)
+	fmt.Fprintf(w, "var %sLeaves = [%d][32]uint8{\n", name, len(t.leaves))
+	for i, lt := range t.leaves {
 		fmt.Fprintf(w, "\t// Leaf table %d\n", i)
 		fmt.Fprint(w, "\t{\n")
 		for row := 0; row < 32; row += 16 {
@@ -727,10 +1216,54 @@ package uniwidth
 	}
 	fmt.Fprint(w, "}\n")
 
-	if err := w.Flush(); err != nil {
-		return fmt.Errorf("failed to flush writer: %w", err)
+}
+
+// writePropertyTable writes the root, middle and leaf arrays of a property
+// table as <name>Root, <name>Middle and <name>Leaves.
+func writePropertyTable(w *bufio.Writer, name string, t *propertyTable) {
+	fmt.Fprintf(w, "// %sRoot maps the top 8 bits of a codepoint (cp >> 13) to a middle table index.\n", name)
+	fmt.Fprintf(w, "// Size: 256 bytes.\n")
+	fmt.Fprintf(w, "var %sRoot = [256]uint8{\n", name)
+	writeBytes(w, "\t", t.root[:])
+	fmt.Fprint(w, "}\n\n")
+
+	fmt.Fprintf(w, "// %sMiddle contains %d unique middle sub-tables.\n", name, len(t.middle))
+	fmt.Fprintf(w, "// Each sub-table has 64 entries mapping bits [12:7] to a leaf table index.\n")
+	fmt.Fprintf(w, "// Size: %d bytes.\n", len(t.middle)*64)
+	fmt.Fprintf(w, "var %sMiddle = [%d][64]uint8{\n", name, len(t.middle))
+	for i, mt := range t.middle {
+		fmt.Fprintf(w, "\t// Middle table %d\n", i)
+		fmt.Fprint(w, "\t{\n")
+		writeBytes(w, "\t\t", mt[:])
+		fmt.Fprint(w, "\t},\n")
+	}
+	fmt.Fprint(w, "}\n\n")
+
+	fmt.Fprintf(w, "// %sLeaves contains %d unique leaf sub-tables.\n", name, len(t.leaves))
+	fmt.Fprintf(w, "// Each sub-table has 128 bytes of property values (one per codepoint).\n")
+	fmt.Fprintf(w, "// Size: %d bytes.\n", len(t.leaves)*128)
+	fmt.Fprintf(w, "var %sLeaves = [%d][128]uint8{\n", name, len(t.leaves))
+	for i, lt := range t.leaves {
+		fmt.Fprintf(w, "\t// Leaf table %d\n", i)
+		fmt.Fprint(w, "\t{\n")
+		writeBytes(w, "\t\t", lt[:])
+		fmt.Fprint(w, "\t},\n")
+	}
+	fmt.Fprint(w, "}\n")
+}
+
+// writeBytes writes b as rows of 16 hex bytes, each row prefixed by indent.
+func writeBytes(w *bufio.Writer, indent string, b []byte) {
+	for row := 0; row < len(b); row += 16 {
+		fmt.Fprint(w, indent)
+		for j := row; j < min(row+16, len(b)); j++ {
+			if j > row {
+				fmt.Fprint(w, " ")
+			}
+			fmt.Fprintf(w, "0x%02X,", b[j])
+		}
+		fmt.Fprint(w, "\n")
 	}
-	return nil
 }
 
 // writeComment writes a single-line Go comment to the writer.
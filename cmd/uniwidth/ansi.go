@@ -0,0 +1,82 @@
+package main
+
+import "strings"
+
+// stripANSI removes ANSI escape sequences from s:
+//   - CSI sequences (ESC [ ... final byte, or the C1 form U+009B), such as
+//     SGR colors "\x1b[31m" and cursor movement
+//   - string sequences (OSC, DCS, SOS, PM and APC), such as hyperlinks
+//     "\x1b]8;;url\x1b\\", terminated by BEL or ST (ESC \)
+//   - two-byte and charset escapes (ESC 7, ESC ( B)
+//
+// An unterminated sequence at the end of s is removed as well.
+func stripANSI(s string) string {
+	if !strings.ContainsAny(s, "\x1b\u009b") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == 0x1B:
+			i = skipEscape(s, i+1)
+		case strings.HasPrefix(s[i:], "\u009b"):
+			i = skipCSI(s, i+len("\u009b"))
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// skipEscape returns the index just past the escape sequence whose ESC byte
+// precedes s[i].
+func skipEscape(s string, i int) int {
+	if i >= len(s) {
+		return i
+	}
+	switch s[i] {
+	case '[':
+		return skipCSI(s, i+1)
+	case ']', 'P', 'X', '^', '_': // OSC, DCS, SOS, PM, APC
+		return skipString(s, i+1)
+	}
+
+	// ESC, intermediate bytes (0x20-0x2F), final byte (0x30-0x7E).
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2F {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x30 && s[i] <= 0x7E {
+		i++
+	}
+	return i
+}
+
+// skipCSI returns the index just past the CSI sequence whose parameters start
+// at s[i]: parameter bytes (0x30-0x3F), intermediate bytes (0x20-0x2F) and a
+// final byte (0x40-0x7E).
+func skipCSI(s string, i int) int {
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x3F {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x40 && s[i] <= 0x7E {
+		i++
+	}
+	return i
+}
+
+// skipString returns the index just past the control string starting at
+// s[i], which ends with BEL or ST (ESC \).
+func skipString(s string, i int) int {
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == 0x07:
+			return i + 1
+		case s[i] == 0x1B && i+1 < len(s) && s[i+1] == '\\':
+			return i + 2
+		}
+	}
+	return i
+}
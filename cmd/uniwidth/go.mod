@@ -0,0 +1,10 @@
+module github.com/unilibs/uniwidth/cmd/uniwidth
+
+go 1.25.1
+
+replace github.com/unilibs/uniwidth => ../..
+
+require (
+	github.com/unilibs/uniwidth v0.0.0-00010101000000-000000000000
+	golang.org/x/text v0.40.0
+)
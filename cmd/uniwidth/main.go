@@ -0,0 +1,360 @@
+// uniwidth measures and inspects the display width of text.
+//
+// It prints the total width of each input line followed by a per-cluster
+// breakdown: byte offset, cluster width, and every rune with its codepoint,
+// Unicode name, the lookup tier that resolved it, and its width. It is meant
+// for debugging misaligned output ("why is this line one column too wide?").
+//
+// Usage:
+//
+//	uniwidth [flags] [text ...]
+//
+// With arguments, the arguments are joined with spaces and measured as one
+// line. Without arguments, each line of standard input is measured.
+//
+// Flags:
+//
+//	-ambiguous narrow|wide   width of East Asian Ambiguous characters
+//	-profile default|east-asian|glibc
+//	                         default:    StringWidth (ambiguous = narrow)
+//	                         east-asian: ambiguous = wide
+//	                         glibc:      POSIX wcwidth()/wcswidth(), no clusters
+//	-strip-ansi              remove ANSI escape sequences before measuring
+//	-json                    print one JSON object per line of input
+//
+// The tool lives in its own module so that the library keeps zero
+// dependencies; it uses only the public uniwidth API. Install it with:
+//
+//	cd cmd/uniwidth && go install .
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/unilibs/uniwidth"
+	"golang.org/x/text/unicode/runenames"
+)
+
+const (
+	profileDefault   = "default"
+	profileEastAsian = "east-asian"
+	profileGlibc     = "glibc"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// config holds the parsed command-line flags.
+type config struct {
+	profile   string
+	opts      []uniwidth.Option
+	stripANSI bool
+	json      bool
+}
+
+// run is the testable body of main: it parses args, measures the input and
+// returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("uniwidth", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: uniwidth [flags] [text ...]")
+		fmt.Fprintln(stderr, "Measures text given as arguments, or each line of standard input.")
+		fs.PrintDefaults()
+	}
+
+	ambiguous := fs.String("ambiguous", "", "width of East Asian Ambiguous characters: narrow or wide (default from -profile)")
+	profile := fs.String("profile", profileDefault, "width profile: default, east-asian or glibc")
+	stripANSI := fs.Bool("strip-ansi", false, "remove ANSI escape sequences before measuring")
+	jsonOut := fs.Bool("json", false, "print one JSON object per line of input")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	cfg, err := newConfig(*profile, *ambiguous)
+	if err != nil {
+		fmt.Fprintf(stderr, "uniwidth: %v\n", err)
+		return exitUsage
+	}
+	cfg.stripANSI = *stripANSI
+	cfg.json = *jsonOut
+
+	out := bufio.NewWriter(stdout)
+	err = cfg.reportAll(out, fs.Args(), stdin)
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "uniwidth: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// reportAll measures the arguments joined with spaces, or every line of
+// stdin when there are no arguments.
+func (c *config) reportAll(w io.Writer, args []string, stdin io.Reader) error {
+	if len(args) > 0 {
+		return c.report(w, strings.Join(args, " "), true)
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for first := true; scanner.Scan(); first = false {
+		if err := c.report(w, strings.TrimSuffix(scanner.Text(), "\r"), first); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+	return nil
+}
+
+// newConfig validates the -profile and -ambiguous flags and translates them
+// into uniwidth options.
+func newConfig(profile, ambiguous string) (*config, error) {
+	cfg := &config{profile: profile}
+
+	var eaWidth uniwidth.EAWidth
+	switch profile {
+	case profileDefault:
+	case profileEastAsian:
+		eaWidth = uniwidth.EAWide
+	case profileGlibc:
+		if ambiguous != "" {
+			return nil, errors.New("-ambiguous cannot be combined with -profile glibc (wcwidth treats ambiguous characters as narrow)")
+		}
+	default:
+		return nil, fmt.Errorf("unknown profile %q (want default, east-asian or glibc)", profile)
+	}
+
+	switch ambiguous {
+	case "":
+	case "narrow":
+		eaWidth = uniwidth.EANarrow
+	case "wide":
+		eaWidth = uniwidth.EAWide
+	default:
+		return nil, fmt.Errorf("unknown ambiguous width %q (want narrow or wide)", ambiguous)
+	}
+
+	// The default profile without -ambiguous measures exactly like StringWidth.
+	if eaWidth != 0 {
+		cfg.opts = append(cfg.opts, uniwidth.WithEastAsianAmbiguous(eaWidth))
+	}
+	return cfg, nil
+}
+
+// lineReport is the measurement of one line of input.
+type lineReport struct {
+	Text     string          `json:"text"`
+	Width    int             `json:"width"`
+	Clusters []clusterReport `json:"clusters"`
+}
+
+// clusterReport is one cluster of a line. Offset is in bytes.
+type clusterReport struct {
+	Offset int          `json:"offset"`
+	Text   string       `json:"text"`
+	Width  int          `json:"width"`
+	Runes  []runeReport `json:"runes"`
+}
+
+// runeReport describes one rune of a cluster. Tier is the lookup tier of
+// uniwidth.RuneWidth that resolved the rune, or "wcwidth" for the glibc
+// profile.
+type runeReport struct {
+	Codepoint string `json:"codepoint"`
+	Name      string `json:"name"`
+	Tier      string `json:"tier"`
+	Width     int    `json:"width"`
+}
+
+// report measures line and writes the result to w; first is false for every
+// line after the first, which is separated by a blank line in text output.
+func (c *config) report(w io.Writer, line string, first bool) error {
+	if c.stripANSI {
+		line = stripANSI(line)
+	}
+	rep := c.measure(line)
+
+	if c.json {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(rep)
+	}
+
+	if !first {
+		fmt.Fprintln(w)
+	}
+	writeText(w, rep)
+	return nil
+}
+
+// measure builds the report for line under the configured profile.
+func (c *config) measure(line string) lineReport {
+	rep := lineReport{Text: line, Clusters: []clusterReport{}}
+
+	var clusters iter.Seq2[string, int]
+	if c.profile == profileGlibc {
+		rep.Width = uniwidth.Wcswidth(line)
+		clusters = wcwidthRunes(line)
+	} else {
+		if c.opts == nil {
+			rep.Width = uniwidth.StringWidth(line)
+		} else {
+			rep.Width = uniwidth.StringWidthWithOptions(line, c.opts...)
+		}
+		clusters = uniwidth.Clusters(line, c.opts...)
+	}
+
+	offset := 0
+	for cluster, width := range clusters {
+		cr := clusterReport{Offset: offset, Text: cluster, Width: width}
+		for i, r := range cluster {
+			cr.Runes = append(cr.Runes, c.describe(cluster[i:], r))
+		}
+		rep.Clusters = append(rep.Clusters, cr)
+		offset += len(cluster)
+	}
+	return rep
+}
+
+// describe reports rune r, which starts s.
+func (c *config) describe(s string, r rune) runeReport {
+	rr := runeReport{
+		Codepoint: fmt.Sprintf("U+%04X", r),
+		Name:      runeName(r),
+	}
+
+	if r == utf8.RuneError {
+		if _, size := utf8.DecodeRuneInString(s); size == 1 {
+			rr.Codepoint = fmt.Sprintf("0x%02X", s[0])
+			rr.Name = "<invalid UTF-8>"
+		}
+	}
+
+	if c.profile == profileGlibc {
+		rr.Tier = "wcwidth"
+		rr.Width = uniwidth.Wcwidth(r)
+		if rr.Name == "<invalid UTF-8>" {
+			rr.Width = -1
+		}
+		return rr
+	}
+
+	rr.Tier = uniwidth.RuneTier(r).String()
+	if c.opts == nil {
+		rr.Width = uniwidth.RuneWidth(r)
+	} else {
+		rr.Width = uniwidth.RuneWidthWithOptions(r, c.opts...)
+	}
+	return rr
+}
+
+// wcwidthRunes yields every rune of s on its own with its Wcwidth: POSIX
+// wcswidth() has no notion of clusters. Invalid bytes have width -1.
+func wcwidthRunes(s string) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		for rest := s; len(rest) > 0; {
+			r, size := utf8.DecodeRuneInString(rest)
+			width := uniwidth.Wcwidth(r)
+			if r == utf8.RuneError && size == 1 {
+				width = -1
+			}
+			if !yield(rest[:size], width) {
+				return
+			}
+			rest = rest[size:]
+		}
+	}
+}
+
+// runeName returns the Unicode name of r, or a placeholder for runes the
+// name tables do not cover.
+func runeName(r rune) string {
+	if name := runenames.Name(r); name != "" {
+		return name
+	}
+	return "<unnamed>"
+}
+
+// writeText writes rep as a human-readable table.
+func writeText(w io.Writer, rep lineReport) {
+	fmt.Fprintf(w, "text:  %s\n", strconv.Quote(rep.Text))
+	fmt.Fprintf(w, "width: %d\n", rep.Width)
+	if len(rep.Clusters) == 0 {
+		return
+	}
+
+	// The cluster column is padded by display width, so it is measured
+	// with uniwidth itself rather than by byte or rune count.
+	const clusterHeader = "CLUSTER"
+	column := uniwidth.StringWidth(clusterHeader)
+	shown := make([]string, len(rep.Clusters))
+	for i, cr := range rep.Clusters {
+		shown[i] = displayCluster(cr.Text)
+		column = max(column, uniwidth.StringWidth(shown[i]))
+	}
+
+	fmt.Fprintf(w, "\n%-6s  %-5s  %s  %s\n", "OFFSET", "WIDTH", pad(clusterHeader, column), "RUNES")
+	for i, cr := range rep.Clusters {
+		for j, rr := range cr.Runes {
+			desc := fmt.Sprintf("%s %s (%s, %d)", rr.Codepoint, rr.Name, rr.Tier, rr.Width)
+			if j == 0 {
+				fmt.Fprintf(w, "%-6d  %-5d  %s  %s\n", cr.Offset, cr.Width, pad(shown[i], column), desc)
+			} else {
+				fmt.Fprintf(w, "%-6s  %-5s  %s  %s\n", "", "", pad("", column), desc)
+			}
+		}
+	}
+}
+
+// displayCluster returns cluster as it should appear in the CLUSTER column:
+// as-is when it renders visibly on its own, or Go-escaped (without quotes)
+// when it contains controls or does not render on its own (zero width).
+func displayCluster(cluster string) string {
+	visible := uniwidth.StringWidth(cluster) > 0 && utf8.ValidString(cluster)
+	for _, r := range cluster {
+		if unicode.IsControl(r) {
+			visible = false
+		}
+	}
+	if visible {
+		return cluster
+	}
+	q := strconv.QuoteToASCII(cluster)
+	return q[1 : len(q)-1]
+}
+
+// pad right-pads s with spaces to the given display width.
+func pad(s string, width int) string {
+	if n := width - uniwidth.StringWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRun_Text(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"e\u0301", "世"}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("run() = %d, stderr: %s", code, stderr.String())
+	}
+
+	want := "text:  \"e\u0301 世\"\n" +
+		"width: 4\n" +
+		"\n" +
+		"OFFSET  WIDTH  CLUSTER  RUNES\n" +
+		"0       1      e\u0301        U+0065 LATIN SMALL LETTER E (ascii, 1)\n" +
+		"                        U+0301 COMBINING ACUTE ACCENT (zero-width, 0)\n" +
+		"3       1               U+0020 SPACE (ascii, 1)\n" +
+		"4       2      世       U+4E16 <CJK Ideograph> (cjk, 2)\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		widths   []int // total width per line
+		clusters []int // cluster count per line
+	}{
+		{"stdin lines", []string{"-json"}, "abc\n👨‍👩‍👧\n", []int{3, 2}, []int{3, 1}},
+		{"CRLF input", []string{"-json"}, "世\r\n", []int{2}, []int{1}},
+		{"ambiguous wide", []string{"-json", "-ambiguous", "wide", "±½"}, "", []int{4}, []int{2}},
+		{"east-asian profile", []string{"-json", "-profile", "east-asian", "±"}, "", []int{2}, []int{1}},
+		{"east-asian narrowed", []string{"-json", "-profile", "east-asian", "-ambiguous", "narrow", "±"}, "", []int{1}, []int{1}},
+		{"glibc profile", []string{"-json", "-profile", "glibc", "👍🏽"}, "", []int{4}, []int{2}},
+		{"glibc control", []string{"-json", "-profile", "glibc", "a\tb"}, "", []int{-1}, []int{3}},
+		{"strip ANSI", []string{"-json", "-strip-ansi", "\x1b[1;31mred\x1b[0m"}, "", []int{3}, []int{3}},
+		{"keep ANSI", []string{"-json", "\x1b[0m"}, "", []int{3}, []int{4}},
+		{"empty line", []string{"-json"}, "\n", []int{0}, []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); code != exitOK {
+				t.Fatalf("run() = %d, stderr: %s", code, stderr.String())
+			}
+
+			dec := json.NewDecoder(&stdout)
+			for i := range tt.widths {
+				var rep lineReport
+				if err := dec.Decode(&rep); err != nil {
+					t.Fatalf("line %d: decode: %v", i, err)
+				}
+				if rep.Width != tt.widths[i] {
+					t.Errorf("line %d: width = %d, want %d", i, rep.Width, tt.widths[i])
+				}
+				if len(rep.Clusters) != tt.clusters[i] {
+					t.Errorf("line %d: %d clusters, want %d", i, len(rep.Clusters), tt.clusters[i])
+				}
+			}
+			if dec.More() {
+				t.Errorf("unexpected extra output after %d lines", len(tt.widths))
+			}
+		})
+	}
+}
+
+func TestRun_JSONRuneDetails(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-json", "a\u0301"}, nil, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run() = %d, stderr: %s", code, stderr.String())
+	}
+
+	var rep lineReport
+	if err := json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(rep.Clusters) != 1 || len(rep.Clusters[0].Runes) != 2 {
+		t.Fatalf("clusters = %+v, want one cluster of two runes", rep.Clusters)
+	}
+
+	want := runeReport{Codepoint: "U+0301", Name: "COMBINING ACUTE ACCENT", Tier: "zero-width", Width: 0}
+	if got := rep.Clusters[0].Runes[1]; got != want {
+		t.Errorf("rune = %+v, want %+v", got, want)
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"-nope"}},
+		{"unknown profile", []string{"-profile", "vt100", "x"}},
+		{"unknown ambiguous", []string{"-ambiguous", "medium", "x"}},
+		{"ambiguous with glibc", []string{"-profile", "glibc", "-ambiguous", "wide", "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, nil, &stdout, &stderr); code != exitUsage {
+				t.Errorf("run(%q) = %d, want %d", tt.args, code, exitUsage)
+			}
+			if stderr.Len() == 0 {
+				t.Errorf("run(%q) wrote nothing to stderr", tt.args)
+			}
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello", "hello"},
+		{"SGR", "\x1b[1;31mred\x1b[0m", "red"},
+		{"cursor movement", "a\x1b[2Kb\x1b[10;20Hc", "abc"},
+		{"private CSI", "\x1b[?25lx\x1b[?25h", "x"},
+		{"C1 CSI", "\u009b31mred", "red"},
+		{"OSC hyperlink ST", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"OSC title BEL", "\x1b]0;title\x07text", "text"},
+		{"charset", "\x1b(Bx", "x"},
+		{"two-byte", "\x1b7x\x1b8", "x"},
+		{"unterminated CSI", "x\x1b[31", "x"},
+		{"lone ESC", "x\x1b", "x"},
+		{"wide text kept", "\x1b[32m世界\x1b[0m", "世界"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripANSI(tt.in); got != tt.want {
+				t.Errorf("stripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
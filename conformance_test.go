@@ -117,7 +117,7 @@ func TestUnicodeConformance_EdgeCases(t *testing.T) {
 		{"Before Hangul", 0xABFF, 1},
 		{"Hangul start", 0xAC00, 2},
 		{"Hangul end", 0xD7AF, 2},
-		{"After Hangul", 0xD7B0, 1},
+		{"After Hangul", 0xD7B0, 0}, // Jungseong O-YEO: medial vowel, conjoins (zero width)
 
 		// Boundary of Hiragana/Katakana
 		// Note: U+303F (IDEOGRAPHIC HALF FILL SPACE) is W in Unicode 16.0 but falls
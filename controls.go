@@ -0,0 +1,116 @@
+package uniwidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ControlPolicy selects how control characters are rendered, and so how wide
+// they are: the C0 controls (U+0000-U+001F), DEL (U+007F) and the C1
+// controls (U+0080-U+009F). It applies to every control, including tab and
+// newline; callers that expand tabs or split lines do so first.
+type ControlPolicy int
+
+const (
+	// ControlZero treats controls as invisible (width 0), as a terminal
+	// that interprets them does. This is the default.
+	ControlZero ControlPolicy = iota
+
+	// ControlCaret renders controls in caret notation, as less and cat -v
+	// do: ^A for U+0001 and ^? for DEL (width 2), and M-^A for the C1
+	// control U+0081 (width 4).
+	ControlCaret
+
+	// ControlHex renders controls as their code point in angle brackets,
+	// <U+0001> (width 8).
+	ControlHex
+
+	// ControlReplacement renders every control as U+FFFD REPLACEMENT
+	// CHARACTER (width 1).
+	ControlReplacement
+)
+
+// width returns the width of the control character r under policy p.
+func (p ControlPolicy) width(r rune) int {
+	switch p {
+	case ControlCaret:
+		if r >= 0x80 {
+			return len("M-^A")
+		}
+		return len("^A")
+	case ControlHex:
+		return len("<U+0001>")
+	case ControlReplacement:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Escape returns s with every control character replaced by its visible
+// rendering under policy: caret notation, <U+XXXX>, U+FFFD, or nothing for
+// ControlZero. Everything else, including invalid UTF-8, is unchanged.
+//
+// The result, which contains no controls, measures the same as s does with
+// WithControlPolicy(policy) added to the same options. For ControlZero the
+// controls are removed, which can let the runes around them join (an emoji,
+// a control and a skin tone become one emoji).
+//
+// Example:
+//
+//	uniwidth.Escape("a\x01b\x7f", uniwidth.ControlCaret) // "a^Ab^?"
+//	uniwidth.Escape("a\x01b", uniwidth.ControlHex)       // "a<U+0001>b"
+func Escape(s string, policy ControlPolicy) string {
+	i := indexControl(s)
+	if i < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for i >= 0 {
+		b.WriteString(s[:i])
+		r, size := utf8.DecodeRuneInString(s[i:])
+		writeControl(&b, r, policy)
+		s = s[i+size:]
+		i = indexControl(s)
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// indexControl returns the byte offset of the first control character in s,
+// or -1 if there is none.
+func indexControl(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c < 0x20 || c == 0x7F:
+			return i
+		case c == 0xC2 && i+1 < len(s) && s[i+1] >= 0x80 && s[i+1] <= 0x9F:
+			return i // U+0080-U+009F are encoded as C2 80 - C2 9F
+		}
+	}
+	return -1
+}
+
+// writeControl writes the rendering of the control character r under policy.
+func writeControl(b *strings.Builder, r rune, policy ControlPolicy) {
+	const hex = "0123456789ABCDEF"
+
+	switch policy {
+	case ControlCaret:
+		if r >= 0x80 {
+			b.WriteString("M-")
+			r -= 0x80
+		}
+		b.WriteByte('^')
+		b.WriteByte(byte(r) ^ 0x40) // 0x01 -> 'A', 0x7F -> '?'
+	case ControlHex:
+		b.WriteString("<U+00")
+		b.WriteByte(hex[r>>4])
+		b.WriteByte(hex[r&0xF])
+		b.WriteByte('>')
+	case ControlReplacement:
+		b.WriteRune(utf8.RuneError)
+	}
+}
@@ -0,0 +1,139 @@
+package uniwidth
+
+import "testing"
+
+func TestControlPolicy_Width(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      rune
+		policy ControlPolicy
+		want   int
+	}{
+		{"NUL zero", 0x00, ControlZero, 0},
+		{"SOH caret", 0x01, ControlCaret, 2},
+		{"DEL caret", 0x7F, ControlCaret, 2},
+		{"C1 caret", 0x85, ControlCaret, 4},
+		{"tab hex", '\t', ControlHex, 8},
+		{"C1 hex", 0x9F, ControlHex, 8},
+		{"ESC replacement", 0x1B, ControlReplacement, 1},
+		{"C1 replacement", 0x80, ControlReplacement, 1},
+		{"not a control", 'a', ControlCaret, 1},
+		{"NBSP is not a control", 0xA0, ControlHex, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RuneWidthWithOptions(tt.r, WithControlPolicy(tt.policy)); got != tt.want {
+				t.Errorf("RuneWidthWithOptions(%U) = %d, want %d", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringWidthWithOptions_ControlPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		zero        int
+		caret       int
+		hex         int
+		replacement int
+	}{
+		{"ASCII short", "a\x01b", 2, 4, 10, 3},
+		{"ASCII long", "column one\tcolumn two\x7f\r\n", 20, 28, 52, 24},
+		{"ASCII without controls", "The quick brown fox jumps over the lazy dog", 43, 43, 43, 43},
+		{"only controls", "\x00\x1f\x7f", 0, 6, 24, 3},
+		{"CR LF", "a\r\nb", 2, 6, 18, 4},
+		{"C1", "a\u0085b", 2, 6, 10, 3},
+		{"non-ASCII", "世界\x01", 4, 6, 12, 5},
+		{"control ends emoji sequence", "👍\x01🏽", 4, 6, 12, 5},
+		{"combining mark after control", "e\ń", 1, 3, 9, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for policy, want := range []int{tt.zero, tt.caret, tt.hex, tt.replacement} {
+				opt := WithControlPolicy(ControlPolicy(policy))
+				if got := StringWidthWithOptions(tt.s, opt); got != want {
+					t.Errorf("StringWidthWithOptions(%q, policy %d) = %d, want %d", tt.s, policy, got, want)
+				}
+
+				sum := 0
+				for _, w := range Clusters(tt.s, opt) {
+					sum += w
+				}
+				if sum != want {
+					t.Errorf("Clusters(%q, policy %d) widths add up to %d, want %d", tt.s, policy, sum, want)
+				}
+			}
+		})
+	}
+}
+
+// TestStringWidthWithOptions_ASCIIControls checks that the ASCII fast path
+// of the options API agrees with StringWidth (it used to return len(s)).
+func TestStringWidthWithOptions_ASCIIControls(t *testing.T) {
+	for _, s := range []string{"a\x01b", "\t", "line\n", "a long line with a tab\there and a DEL\x7f"} {
+		if got, want := StringWidthWithOptions(s), StringWidth(s); got != want {
+			t.Errorf("StringWidthWithOptions(%q) = %d, StringWidth = %d", s, got, want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		policy ControlPolicy
+		want   string
+	}{
+		{"no controls", "héllo 世界", ControlCaret, "héllo 世界"},
+		{"caret", "a\x01b\x7f", ControlCaret, "a^Ab^?"},
+		{"caret NUL and US", "\x00\x1f", ControlCaret, "^@^_"},
+		{"caret CR LF", "a\r\n", ControlCaret, "a^M^J"},
+		{"caret C1", "\u0080\u009f", ControlCaret, "M-^@M-^_"},
+		{"hex", "a\x01b", ControlHex, "a<U+0001>b"},
+		{"hex DEL and C1", "\x7f\u009b", ControlHex, "<U+007F><U+009B>"},
+		{"replacement", "a\x1b[31mb", ControlReplacement, "a�[31mb"},
+		{"zero removes", "a\x01b\u0085c", ControlZero, "abc"},
+		{"invalid UTF-8 kept", "a\xc2\x01\xff", ControlCaret, "a\xc2^A\xff"},
+		{"C2 not followed by C1", " Â", ControlHex, " Â"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Escape(tt.s, tt.policy); got != tt.want {
+				t.Errorf("Escape(%q, %d) = %q, want %q", tt.s, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEscape_Width checks that the escaped text is as wide as the options
+// API measures the original.
+func TestEscape_Width(t *testing.T) {
+	inputs := []string{
+		"a\x01b",
+		"tab\there\r\n",
+		"世界\x7f\u0085",
+		"👍\x01🏽",
+		"😀‍\x01😀",
+		"1\x01⃣",
+		"\x01️",
+		"e\ń",
+		"🇺\x01🇸",
+		"ᄀ\x01ᅡ",
+	}
+
+	for _, s := range inputs {
+		for _, policy := range []ControlPolicy{ControlCaret, ControlHex, ControlReplacement} {
+			want := StringWidthWithOptions(s, WithControlPolicy(policy))
+			if got := StringWidthWithOptions(Escape(s, policy)); got != want {
+				t.Errorf("width of Escape(%q, %d) = %d, want %d", s, policy, got, want)
+			}
+			if got := StringWidth(Escape(s, policy)); got != want {
+				t.Errorf("StringWidth(Escape(%q, %d)) = %d, want %d", s, policy, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package uniwidth
+
+import "unicode/utf8"
+
+// EAProperty is a Unicode East_Asian_Width property value (UAX #11).
+//
+// Unlike the widths returned by RuneWidth, it keeps all six classes, so
+// callers can tell Halfwidth katakana (H) from ordinary Narrow characters
+// (Na), or Fullwidth forms (F) from other Wide characters (W).
+type EAProperty uint8
+
+const (
+	// EastAsianNeutral (N) is a character that does not occur in legacy
+	// East Asian encodings, such as most non-CJK scripts. It is also the
+	// value of every codepoint that EastAsianWidth.txt does not list.
+	EastAsianNeutral EAProperty = iota
+
+	// EastAsianAmbiguous (A) is a character that is wide in East Asian
+	// contexts and narrow elsewhere, such as ± and the box drawing set.
+	EastAsianAmbiguous
+
+	// EastAsianHalfwidth (H) is a character with a fullwidth counterpart
+	// in legacy encodings, such as halfwidth katakana (ｱ) and U+20A9 ₩.
+	EastAsianHalfwidth
+
+	// EastAsianWide (W) is a wide character, such as CJK ideographs, kana,
+	// Hangul syllables and emoji with Emoji_Presentation.
+	EastAsianWide
+
+	// EastAsianFullwidth (F) is a fullwidth form with a narrow counterpart,
+	// such as Ａ (U+FF21) and the ideographic space U+3000.
+	EastAsianFullwidth
+
+	// EastAsianNarrow (Na) is a narrow character with a fullwidth
+	// counterpart, such as ASCII.
+	EastAsianNarrow
+)
+
+// String returns the short property value alias used in EastAsianWidth.txt:
+// "N", "A", "H", "W", "F" or "Na".
+func (p EAProperty) String() string {
+	switch p {
+	case EastAsianNeutral:
+		return "N"
+	case EastAsianAmbiguous:
+		return "A"
+	case EastAsianHalfwidth:
+		return "H"
+	case EastAsianWide:
+		return "W"
+	case EastAsianFullwidth:
+		return "F"
+	case EastAsianNarrow:
+		return "Na"
+	default:
+		return "unknown"
+	}
+}
+
+// EastAsianWidthOf returns the East_Asian_Width property of r, as listed in
+// EastAsianWidth.txt. Invalid runes are Neutral.
+//
+// The property is the raw Unicode data: it does not account for combining
+// marks, controls, emoji sequences or the options that RuneWidth and
+// StringWidthWithOptions apply.
+//
+// Example:
+//
+//	uniwidth.EastAsianWidthOf('ｱ') // EastAsianHalfwidth
+//	uniwidth.EastAsianWidthOf('Ａ') // EastAsianFullwidth
+//	uniwidth.EastAsianWidthOf('a') // EastAsianNarrow
+//
+// Performance: O(1), 0 allocations (3-stage table lookup).
+func EastAsianWidthOf(r rune) EAProperty {
+	if r < 0 || r > utf8.MaxRune {
+		return EastAsianNeutral
+	}
+	cp := uint32(r) //nolint:gosec // G115: r is within 0–0x10FFFF here
+	midIdx := eastAsianWidthMiddle[eastAsianWidthRoot[cp>>13]][cp>>7&0x3F]
+	return EAProperty(eastAsianWidthLeaves[midIdx][cp&0x7F])
+}
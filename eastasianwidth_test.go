@@ -0,0 +1,127 @@
+package uniwidth
+
+import "testing"
+
+// TestEastAsianWidthOf_Exhaustive verifies the generated East Asian Width
+// table against the trimmed copy of EastAsianWidth.txt in testdata, for
+// every codepoint. Codepoints that the file does not list must be Neutral.
+// The check is only independent of the generator if the copy was trimmed
+// from the published file (see its Source line).
+func TestEastAsianWidthOf_Exhaustive(t *testing.T) {
+	data := loadUCDRanges(t, "testdata/EastAsianWidth.txt")
+
+	classes := []struct {
+		class  EAProperty
+		ranges []runeRange
+	}{
+		{EastAsianNeutral, data["N"]},
+		{EastAsianAmbiguous, data["A"]},
+		{EastAsianHalfwidth, data["H"]},
+		{EastAsianWide, data["W"]},
+		{EastAsianFullwidth, data["F"]},
+		{EastAsianNarrow, data["Na"]},
+	}
+
+	mismatches := 0
+	const maxMismatchLog = 20
+
+	for cp := rune(0); cp <= 0x10FFFF; cp++ {
+		want := EastAsianNeutral
+		for _, c := range classes {
+			if binarySearch(cp, c.ranges) {
+				want = c.class
+				break
+			}
+		}
+
+		if got := EastAsianWidthOf(cp); got != want {
+			mismatches++
+			if mismatches <= maxMismatchLog {
+				t.Errorf("EastAsianWidthOf(%U) = %v, want %v", cp, got, want)
+			}
+		}
+	}
+
+	if mismatches > maxMismatchLog {
+		t.Errorf("... and %d more mismatches (total: %d)", mismatches-maxMismatchLog, mismatches)
+	}
+}
+
+// TestEastAsianWidthOf_ConsistentWithTables checks that the classes agree
+// with the width tables generated from the same file.
+func TestEastAsianWidthOf_ConsistentWithTables(t *testing.T) {
+	for cp := rune(0); cp <= 0x10FFFF; cp++ {
+		p := EastAsianWidthOf(cp)
+		if (p == EastAsianAmbiguous) != binarySearch(cp, ambiguousTableGenerated) {
+			t.Errorf("%U: EastAsianWidthOf = %v, ambiguousTableGenerated disagrees", cp, p)
+		}
+		if p == EastAsianFullwidth && RuneWidth(cp) != 2 {
+			t.Errorf("%U: Fullwidth but RuneWidth = %d", cp, RuneWidth(cp))
+		}
+	}
+}
+
+func TestEastAsianWidthOf(t *testing.T) {
+	tests := []struct {
+		name string
+		r    rune
+		want EAProperty
+	}{
+		{"ASCII letter", 'a', EastAsianNarrow},
+		{"ASCII space", ' ', EastAsianNarrow},
+		{"control", '\n', EastAsianNeutral},
+		{"NBSP", 0x00A0, EastAsianNeutral},
+		{"Latin s circumflex", 'ŝ', EastAsianNeutral},
+		{"Latin e acute", 'é', EastAsianAmbiguous},
+		{"Hebrew alef", 'א', EastAsianNeutral},
+		{"Cyrillic", 'Ж', EastAsianAmbiguous},
+		{"combining acute", 0x0301, EastAsianAmbiguous},
+		{"plus-minus", '±', EastAsianAmbiguous},
+		{"box drawing", '─', EastAsianAmbiguous},
+		{"halfwidth katakana", 'ｱ', EastAsianHalfwidth},
+		{"won sign", '₩', EastAsianHalfwidth},
+		{"halfwidth Hangul", 'ﾡ', EastAsianHalfwidth},
+		{"CJK ideograph", '世', EastAsianWide},
+		{"hiragana", 'あ', EastAsianWide},
+		{"Hangul syllable", '한', EastAsianWide},
+		{"emoji", '😀', EastAsianWide},
+		{"reserved CJK Extension", 0x3FFFD, EastAsianWide},
+		{"fullwidth A", 'Ａ', EastAsianFullwidth},
+		{"ideographic space", 0x3000, EastAsianFullwidth},
+		{"fullwidth yen", '￥', EastAsianFullwidth},
+		{"cent sign", '¢', EastAsianNarrow},
+		{"private use", 0xE000, EastAsianAmbiguous},
+		{"unassigned", 0x0378, EastAsianNeutral},
+		{"negative", -1, EastAsianNeutral},
+		{"beyond max rune", 0x110000, EastAsianNeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EastAsianWidthOf(tt.r); got != tt.want {
+				t.Errorf("EastAsianWidthOf(%U) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEAProperty_String(t *testing.T) {
+	tests := []struct {
+		p    EAProperty
+		want string
+	}{
+		{EastAsianNeutral, "N"},
+		{EastAsianAmbiguous, "A"},
+		{EastAsianHalfwidth, "H"},
+		{EastAsianWide, "W"},
+		{EastAsianFullwidth, "F"},
+		{EastAsianNarrow, "Na"},
+		{EAProperty(42), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("EAProperty(%d).String() = %q, want %q", uint8(tt.p), got, tt.want)
+		}
+	}
+}
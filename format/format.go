@@ -0,0 +1,271 @@
+// Package format provides fmt-style formatting whose widths and precisions
+// count display columns instead of runes.
+//
+// fmt pads "%-10s" to ten runes, so a string of CJK characters or emoji ends
+// up wider than its column. The functions in this package accept the same
+// format strings and arguments as their fmt counterparts, but for the %s, %q
+// and %v verbs applied to text (strings, errors, fmt.Stringers, and byte
+// slices for %s and %q) the width and precision are measured with uniwidth:
+//
+//	format.Sprintf("|%-6s|%4.3s|", "世界", "日本語")
+//	// "|世界  |  日|"
+//
+// Precision truncates at cluster boundaries, so it never splits an emoji
+// sequence or separates a combining mark from its base; a wide character
+// that would cross the limit is dropped. All other verbs and argument types
+// are formatted by fmt unchanged.
+//
+// To keep using the fmt functions, wrap individual strings in Text instead.
+package format
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/unilibs/uniwidth"
+)
+
+// Printer formats with widths measured by uniwidth.StringWidthWithOptions
+// and a fixed set of options, for example for East Asian terminals where
+// ambiguous characters are wide. The zero Printer measures like
+// uniwidth.StringWidth.
+type Printer struct {
+	opts []uniwidth.Option
+}
+
+// NewPrinter returns a Printer that measures text with opts.
+//
+// Example:
+//
+//	p := format.NewPrinter(uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide))
+//	p.Sprintf("%-4s|", "±") // "±  |" (± is 2 columns wide)
+func NewPrinter(opts ...uniwidth.Option) *Printer {
+	return &Printer{opts: opts}
+}
+
+// std is the Printer behind the package-level functions.
+var std Printer
+
+// Sprintf is like fmt.Sprintf, with widths and precisions of text in
+// display columns.
+func Sprintf(format string, a ...any) string {
+	return std.Sprintf(format, a...)
+}
+
+// Fprintf is like fmt.Fprintf, with widths and precisions of text in
+// display columns.
+func Fprintf(w io.Writer, format string, a ...any) (int, error) {
+	return std.Fprintf(w, format, a...)
+}
+
+// Printf is like fmt.Printf, with widths and precisions of text in display
+// columns.
+func Printf(format string, a ...any) (int, error) {
+	return std.Printf(format, a...)
+}
+
+// Errorf is like fmt.Errorf, with widths and precisions of text in display
+// columns. The %w verb wraps errors as with fmt.Errorf.
+func Errorf(format string, a ...any) error {
+	return std.Errorf(format, a...)
+}
+
+// Sprintf is like fmt.Sprintf, with widths and precisions of text in
+// display columns.
+func (p *Printer) Sprintf(format string, a ...any) string {
+	return fmt.Sprintf(format, p.wrap(format, a)...)
+}
+
+// Fprintf is like fmt.Fprintf, with widths and precisions of text in
+// display columns.
+func (p *Printer) Fprintf(w io.Writer, format string, a ...any) (int, error) {
+	return fmt.Fprintf(w, format, p.wrap(format, a)...)
+}
+
+// Printf is like fmt.Printf, with widths and precisions of text in display
+// columns.
+func (p *Printer) Printf(format string, a ...any) (int, error) {
+	return p.Fprintf(os.Stdout, format, a...)
+}
+
+// Errorf is like fmt.Errorf, with widths and precisions of text in display
+// columns. The %w verb wraps errors as with fmt.Errorf.
+func (p *Printer) Errorf(format string, a ...any) error {
+	return fmt.Errorf(format, p.wrap(format, a)...)
+}
+
+// wrap returns args with every argument that format prints as text replaced
+// by a value that formats in display columns. Arguments that are also used
+// by other verbs (%x, %T, %w, a '*' width, ...) are left alone, so fmt
+// formats them, and reports errors for them, exactly as before.
+func (p *Printer) wrap(format string, args []any) []any {
+	if len(args) == 0 {
+		return args
+	}
+
+	// text[i] is set once args[i] is used by a text verb; other[i] once it
+	// is used in any other way.
+	text := make([]bool, len(args))
+	other := make([]bool, len(args))
+	use := func(arg int, isTextVerb bool) {
+		switch {
+		case arg < 0 || arg >= len(args):
+		case isTextVerb:
+			text[arg] = true
+		default:
+			other[arg] = true
+		}
+	}
+
+	scanDirectives(format, len(args), func(arg int, verb rune, sharp bool) {
+		switch verb {
+		case 's', 'q':
+			use(arg, true)
+		case 'v':
+			// %v prints byte slices as numbers, and %#v prints Go syntax,
+			// which only matches the quoted text for strings.
+			if arg < 0 || arg >= len(args) {
+				break
+			}
+			switch args[arg].(type) {
+			case string, Text:
+				use(arg, true)
+			case []byte:
+				use(arg, false)
+			default:
+				use(arg, !sharp)
+			}
+		default:
+			use(arg, false)
+		}
+	})
+
+	var wrapped []any
+	for i, arg := range args {
+		if !text[i] || other[i] || !isText(arg) {
+			continue
+		}
+		if wrapped == nil {
+			wrapped = make([]any, len(args))
+			copy(wrapped, args)
+		}
+		wrapped[i] = value{arg: arg, opts: p.opts}
+	}
+	if wrapped == nil {
+		return args
+	}
+	return wrapped
+}
+
+// scanDirectives calls fn for every argument that format consumes, in the
+// order fmt consumes them: with the verb, or with verb '*' for a width or
+// precision taken from the arguments. The parsing follows fmt's, including
+// explicit argument indexes such as %[2]s; directives with an invalid index
+// consume no argument.
+func scanDirectives(format string, numArgs int, fn func(arg int, verb rune, sharp bool)) {
+	argNum := 0
+	end := len(format)
+	for i := 0; i < end; {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+		i++
+
+		// Flags.
+		sharp := false
+	flags:
+		for ; i < end; i++ {
+			switch format[i] {
+			case '#':
+				sharp = true
+			case '0', '+', '-', ' ':
+			default:
+				break flags
+			}
+		}
+
+		// Argument index, width and precision. As in fmt, an index must be
+		// followed by '*' or the verb, not by a number.
+		good, afterIndex := true, false
+		index := func() {
+			var ok bool
+			argNum, i, afterIndex, ok = argIndex(format, i, argNum, numArgs)
+			good = good && ok
+		}
+		number := func() {
+			if i < end && format[i] == '*' {
+				if argNum < numArgs {
+					fn(argNum, '*', false)
+					argNum++
+				}
+				i++
+				afterIndex = false
+				return
+			}
+			if j := skipDigits(format, i); j > i {
+				good = good && !afterIndex
+				i = j
+			}
+		}
+		index()
+		number()
+		if i < end && format[i] == '.' {
+			i++
+			good = good && !afterIndex
+			index()
+			number()
+		}
+		if !afterIndex {
+			index()
+		}
+
+		if i >= end {
+			return // "%!(NOVERB)"
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size
+		if verb == '%' || !good {
+			continue // "%%" and "%!v(BADINDEX)" consume no argument
+		}
+		fn(argNum, verb, sharp)
+		argNum++
+	}
+}
+
+// argIndex parses an explicit argument index "[n]" at format[i], if any. It
+// returns the index of the selected argument (argNum without an index, or
+// for an invalid one), the position after the index, whether there was an
+// index and whether it was valid.
+func argIndex(format string, i, argNum, numArgs int) (newArgNum, next int, found, ok bool) {
+	if i >= len(format) || format[i] != '[' {
+		return argNum, i, false, true
+	}
+	for j := i + 1; j < len(format); j++ {
+		if format[j] != ']' {
+			continue
+		}
+		n := 0
+		for _, c := range format[i+1 : j] {
+			if c < '0' || c > '9' || n > numArgs {
+				return argNum, j + 1, true, false
+			}
+			n = n*10 + int(c-'0')
+		}
+		if n < 1 || n > numArgs {
+			return argNum, j + 1, true, false
+		}
+		return n - 1, j + 1, true, true
+	}
+	return argNum, len(format), true, false
+}
+
+// skipDigits returns the position of the first non-digit at or after i.
+func skipDigits(format string, i int) int {
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,236 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/unilibs/uniwidth"
+)
+
+// stringer is a fmt.Stringer.
+type stringer struct{ s string }
+
+func (s stringer) String() string { return s.s }
+
+// ptrError is an error with a pointer receiver.
+type ptrError struct{ msg string }
+
+func (e *ptrError) Error() string { return e.msg }
+
+// goStringer is a fmt.Stringer that also has Go syntax.
+type goStringer struct{ s string }
+
+func (g goStringer) String() string   { return g.s }
+func (g goStringer) GoString() string { return "goStringer" }
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		args   []any
+		want   string
+	}{
+		{"left CJK", "|%-6s|", []any{"世界"}, "|世界  |"},
+		{"right CJK", "|%6s|", []any{"世界"}, "|  世界|"},
+		{"zero padding", "|%06s|", []any{"世界"}, "|00世界|"},
+		{"no width", "|%s|", []any{"世界"}, "|世界|"},
+		{"too wide", "|%2s|", []any{"世界"}, "|世界|"},
+		{"combining marks", "|%-6s|", []any{"café"}, "|café  |"},
+		{"emoji sequence", "|%4s|", []any{"👨‍👩‍👧"}, "|  👨‍👩‍👧|"},
+		{"precision", "|%.3s|", []any{"日本語"}, "|日|"},
+		{"precision on boundary", "|%.4s|", []any{"日本語"}, "|日本|"},
+		{"width and precision", "|%4.3s|", []any{"日本語"}, "|  日|"},
+		{"precision keeps clusters", "|%.3s|", []any{"éééé"}, "|ééé|"},
+		{"precision drops sequence", "|%.1s|", []any{"👍🏽x"}, "||"},
+		{"star width", "|%*s|", []any{6, "世界"}, "|  世界|"},
+		{"star precision", "|%.*s|", []any{2, "世界"}, "|世|"},
+		{"quoted", "|%8q|", []any{"世界"}, `|  "世界"|`},
+		{"quoted precision", "|%.2q|", []any{"世界"}, `|"世"|`},
+		{"quoted ASCII", "|%+-16q|", []any{"世界"}, `|"\u4e16\u754c"  |`},
+		{"value", "|%-6v|", []any{"世界"}, "|世界  |"},
+		{"Go syntax string", "|%#8v|", []any{"世界"}, `|  "世界"|`},
+		{"byte slice", "|%-6s|", []any{[]byte("世界")}, "|世界  |"},
+		{"error", "|%-6v|", []any{errors.New("失敗")}, "|失敗  |"},
+		{"Stringer", "|%-6s|", []any{stringer{"世界"}}, "|世界  |"},
+		{"nil Stringer", "|%-6s|%q|", []any{(*stringer)(nil), (*stringer)(nil)}, "|<nil>|<nil>|"},
+		{"nil error", "|%6v|", []any{(*ptrError)(nil)}, "|<nil>|"},
+		{"Text", "|%-6s|", []any{Text("世界")}, "|世界  |"},
+		{"explicit index", "|%-6[2]s|%[1]s|", []any{"a", "世界"}, "|世界  |a|"},
+		{"index then sequential", "|%[2]s|%s|", []any{"a", "世界", "b"}, "|世界|b|"},
+		{"mixed verbs", "%d %-4s %x", []any{42, "世", 255}, "42 世   ff"},
+		{"table row", "%-8s%5s\n", []any{"名前", "値"}, "名前       値\n"},
+		{"percent", "%d%% %-4s|", []any{50, "世"}, "50% 世  |"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sprintf(tt.format, tt.args...); got != tt.want {
+				t.Errorf("Sprintf(%q, %v) = %q, want %q", tt.format, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSprintf_MatchesFmt checks that for ASCII text, which is one column per
+// rune, and for arguments this package does not handle, the output is
+// exactly fmt's, including fmt's error reports.
+func TestSprintf_MatchesFmt(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []any
+	}{
+		{"%s", []any{"hello"}},
+		{"|%-10s|%10s|%010s|", []any{"a", "b", "c"}},
+		{"|%.2s|%5.1s|%-5.3s|", []any{"hello", "hello", "hello"}},
+		{"|%q|%8q|%-8q|%.2q|%+q|%#q|", []any{"hi", "hi", "hi", "hello", "hi", "a`b"}},
+		{"|%v|%8v|%-8v|%#v|%8.2v|", []any{"a", "b", "c", "d", "hello"}},
+		{"|%x|%X|% x|%8x|", []any{"hi", "hi", "hi", "hi"}},
+		{"%s %d %v %5.2f", []any{"a", 1, true, 3.14159}},
+		{"%[2]s %[1]s %s", []any{"a", "b"}},
+		{"%[3]s", []any{"a", "b"}},
+		{"%[0]s %s", []any{"a"}},
+		{"%[x]s %s", []any{"a"}},
+		{"%*s|%-*s|", []any{5, "a", 5, "b"}},
+		{"%.*s|", []any{2, "hello"}},
+		{"%*s", []any{"x", "a"}},
+		{"%s %s", []any{"a"}},
+		{"%s", []any{"a", "b", 3}},
+		{"%d", []any{"a"}},
+		{"%T %s", []any{"a", "b"}},
+		{"%T %[1]s", []any{"a"}},
+		{"%s", []any{[]string{"a", "b"}}},
+		{"%v", []any{[]byte("ab")}},
+		{"%#v", []any{[]byte("ab")}},
+		{"%#v", []any{stringer{"a"}}},
+		{"%#v %s", []any{goStringer{"a"}, goStringer{"b"}}},
+		{"|%s|%8v|%-8q|%.2s|", []any{(*stringer)(nil), (*stringer)(nil), (*stringer)(nil), (*stringer)(nil)}},
+		{"|%s|%-8v|", []any{(*ptrError)(nil), (*ptrError)(nil)}},
+		{"%8s", []any{nil}},
+		{"%8s", []any{errors.New("boom")}},
+		{"%", []any{"a"}},
+		{"%!", []any{"a"}},
+		{"%-", []any{"a"}},
+		{"%[2]*[1]s|", []any{"a", 4}},
+		{"%-[2]6s|%[2]s|", []any{1, "a"}},
+		{"%[2].[1]s|%s", []any{"a", "b"}},
+		{"%.[2]2s|%s", []any{"a", "b"}},
+		{"%-6[2]s|%s|", []any{1, "a", "b"}},
+		{"%[2]6s %s", []any{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		want := fmt.Sprintf(tt.format, tt.args...)
+		if got := Sprintf(tt.format, tt.args...); got != want {
+			t.Errorf("Sprintf(%q, %#v) = %q, fmt.Sprintf = %q", tt.format, tt.args, got, want)
+		}
+	}
+}
+
+func TestPrinter_EastAsianWide(t *testing.T) {
+	p := NewPrinter(uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide))
+
+	tests := []struct {
+		format string
+		args   []any
+		want   string
+	}{
+		{"%-4s|", []any{"±"}, "±  |"},
+		{"%-4s|", []any{Text("±")}, "±  |"},
+		{"%.3s|", []any{"±±"}, "±|"},
+		{"%-4s|", []any{"世"}, "世  |"},
+	}
+
+	for _, tt := range tests {
+		if got := p.Sprintf(tt.format, tt.args...); got != tt.want {
+			t.Errorf("Sprintf(%q, %v) = %q, want %q", tt.format, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestFprintf(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := Fprintf(&buf, "%-4s|", "世")
+	if err != nil {
+		t.Fatalf("Fprintf() error = %v", err)
+	}
+	if got, want := buf.String(), "世  |"; got != want {
+		t.Errorf("Fprintf() wrote %q, want %q", got, want)
+	}
+	if n != buf.Len() {
+		t.Errorf("Fprintf() = %d, want %d", n, buf.Len())
+	}
+}
+
+func TestPrintf(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, err = Printf("%-4s|", "世")
+	os.Stdout = stdout
+	if cerr := w.Close(); cerr != nil {
+		t.Fatal(cerr)
+	}
+	if err != nil {
+		t.Fatalf("Printf() error = %v", err)
+	}
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "世  |"; got != want {
+		t.Errorf("Printf() wrote %q, want %q", got, want)
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	cause := errors.New("失敗")
+	err := Errorf("%-6s: %w", "読込", cause)
+
+	if got, want := err.Error(), "読込  : 失敗"; got != want {
+		t.Errorf("Errorf().Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Errorf() does not wrap %v", cause)
+	}
+}
+
+func TestScanDirectives(t *testing.T) {
+	type use struct {
+		arg  int
+		verb rune
+	}
+	tests := []struct {
+		format  string
+		numArgs int
+		want    []use
+	}{
+		{"%s %d", 2, []use{{0, 's'}, {1, 'd'}}},
+		{"%% %s", 1, []use{{0, 's'}}},
+		{"%*.*s", 3, []use{{0, '*'}, {1, '*'}, {2, 's'}}},
+		{"%[2]s %s", 3, []use{{1, 's'}, {2, 's'}}},
+		{"%[2]*[1]s", 2, []use{{1, '*'}, {0, 's'}}},
+		{"%[9]s %s", 1, []use{{0, 's'}}},
+		{"%[2]6s %s", 2, []use{{1, 's'}}},
+		{"%-+# 0v", 1, []use{{0, 'v'}}},
+		{"%5.2世", 1, []use{{0, '世'}}},
+		{"abc %", 1, nil},
+	}
+
+	for _, tt := range tests {
+		var got []use
+		scanDirectives(tt.format, tt.numArgs, func(arg int, verb rune, _ bool) {
+			got = append(got, use{arg, verb})
+		})
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("scanDirectives(%q) = %v, want %v", tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/unilibs/uniwidth"
+)
+
+// Text is a string that formats with width and precision in display columns
+// for the %s, %q and %v verbs, so it can be passed to the standard fmt
+// functions directly:
+//
+//	fmt.Printf("|%-8s|%6s|\n", format.Text("世界"), format.Text("é"))
+//	// |世界    |     é|
+//
+// Precision truncates the text to at most that many columns, cutting only
+// at cluster boundaries, so emoji sequences and combining marks are never
+// split. Other verbs format the underlying string as fmt would.
+type Text string
+
+// Format implements fmt.Formatter.
+func (t Text) Format(f fmt.State, verb rune) {
+	formatText(f, verb, string(t), nil)
+}
+
+// String returns t as a plain string.
+func (t Text) String() string {
+	return string(t)
+}
+
+// value wraps an argument of Sprintf and friends that is formatted as text.
+type value struct {
+	arg  any
+	opts []uniwidth.Option
+}
+
+// Format implements fmt.Formatter.
+func (v value) Format(f fmt.State, verb rune) {
+	s, ok := textOf(v.arg)
+	if !ok {
+		// Like fmt, print a nil receiver as <nil>, ignoring the verb, width
+		// and precision.
+		_, _ = io.WriteString(f, s)
+		return
+	}
+	formatText(f, verb, s, v.opts)
+}
+
+// textOf returns the text that the %s and %v verbs print for arg.
+// It is only called for the argument types isText accepts. If the Error or
+// String method of a nil pointer panics, as a method with a value receiver
+// always does, textOf returns "<nil>" and false, as fmt's catchPanic does;
+// other panics are passed on.
+func textOf(arg any) (s string, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			if v := reflect.ValueOf(arg); v.Kind() != reflect.Pointer || !v.IsNil() {
+				panic(err)
+			}
+			s, ok = "<nil>", false
+		}
+	}()
+
+	switch a := arg.(type) {
+	case Text:
+		return string(a), true
+	case string:
+		return a, true
+	case []byte:
+		return string(a), true
+	case error:
+		return a.Error(), true
+	case fmt.Stringer:
+		return a.String(), true
+	}
+	return fmt.Sprint(arg), true
+}
+
+// isText reports whether fmt prints arg as text for the %s, %q and %v
+// verbs: strings, byte slices, errors and fmt.Stringers. Types that
+// implement fmt.Formatter or fmt.GoStringer keep their own formatting.
+func isText(arg any) bool {
+	switch arg.(type) {
+	case Text:
+		return true
+	case fmt.Formatter, fmt.GoStringer:
+		return false
+	case string, []byte, error, fmt.Stringer:
+		return true
+	}
+	return false
+}
+
+// formatText writes s to f for verb, applying the precision and width of f
+// in display columns as measured with opts.
+func formatText(f fmt.State, verb rune, s string, opts []uniwidth.Option) {
+	// quote is the directive that quotes the truncated text, if any.
+	var quote string
+	switch verb {
+	case 's':
+	case 'v':
+		if f.Flag('#') {
+			quote = "%q" // Go syntax of a string
+		}
+	case 'q':
+		quote = "%"
+		if f.Flag('+') {
+			quote += "+"
+		}
+		if f.Flag('#') {
+			quote += "#"
+		}
+		quote += "q"
+	default:
+		// Not a text verb: format the string as fmt would.
+		fmt.Fprintf(f, fmt.FormatString(f, verb), s)
+		return
+	}
+
+	// Like fmt, truncate before quoting and pad the quoted text.
+	if prec, ok := f.Precision(); ok {
+		s = truncate(s, prec, opts)
+	}
+	if quote != "" {
+		s = fmt.Sprintf(quote, s)
+	}
+	if width, ok := f.Width(); ok {
+		s = pad(s, width, f.Flag('-'), f.Flag('0'), opts)
+	}
+	_, _ = io.WriteString(f, s)
+}
+
+// stringWidth returns the display width of s as measured with opts.
+func stringWidth(s string, opts []uniwidth.Option) int {
+	if len(opts) == 0 {
+		return uniwidth.StringWidth(s)
+	}
+	return uniwidth.StringWidthWithOptions(s, opts...)
+}
+
+// truncate returns the longest prefix of s that ends at a cluster boundary
+// and is at most width columns wide.
+func truncate(s string, width int, opts []uniwidth.Option) string {
+	n, w := 0, 0
+	for cluster, cw := range uniwidth.Clusters(s, opts...) {
+		if w+cw > width {
+			break
+		}
+		n += len(cluster)
+		w += cw
+	}
+	return s[:n]
+}
+
+// pad pads s to width columns: with spaces on the right if left is set, and
+// otherwise on the left with spaces, or zeros if zero is set (as fmt does
+// for strings). Text that is already wide enough is unchanged.
+func pad(s string, width int, left, zero bool, opts []uniwidth.Option) string {
+	gap := width - stringWidth(s, opts)
+	if gap <= 0 {
+		return s
+	}
+	if left {
+		return s + strings.Repeat(" ", gap)
+	}
+	if zero {
+		return strings.Repeat("0", gap) + s
+	}
+	return strings.Repeat(" ", gap) + s
+}
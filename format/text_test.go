@@ -0,0 +1,61 @@
+package format
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/unilibs/uniwidth"
+)
+
+func TestText_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		text   Text
+		want   string
+	}{
+		{"|%-8s|", "世界", "|世界    |"},
+		{"|%8s|", "世界", "|    世界|"},
+		{"|%8v|", "世界", "|    世界|"},
+		{"|%.3s|", "世界", "|世|"},
+		{"|%6q|", "世界", `|"世界"|`},
+		{"|%8q|", "世界", `|  "世界"|`},
+		{"|%#v|", "世界", `|"世界"|`},
+		{"|%x|", "hi", "|6869|"},
+		{"|%d|", "hi", "|%!d(string=hi)|"},
+		{"|%s|", "hi", "|hi|"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.text); got != tt.want {
+			t.Errorf("fmt.Sprintf(%q, Text(%q)) = %q, want %q", tt.format, string(tt.text), got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"hello", 0, ""},
+		{"世界", 3, "世"},
+		{"世界", 1, ""},
+		{"👨‍👩‍👧abc", 3, "👨‍👩‍👧a"},
+		{"🇺🇸🇯🇵", 3, "🇺🇸"},
+		{"éx", 1, "é"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.width, nil); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+
+	wide := []uniwidth.Option{uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide)}
+	if got, want := truncate("±±", 3, wide), "±"; got != want {
+		t.Errorf("truncate(%q, 3, EAWide) = %q, want %q", "±±", got, want)
+	}
+}
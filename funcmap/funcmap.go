@@ -0,0 +1,77 @@
+// Package funcmap provides template functions that measure, pad, truncate
+// and wrap text in display columns, for aligning columns of mixed-script text
+// in text/template and html/template output.
+//
+// Example:
+//
+//	tmpl := template.Must(template.New("report").Funcs(funcmap.New()).Parse(
+//		`{{range .}}{{padRight 12 .Name}}{{padLeft 8 .Size}}{{"\n"}}{{end}}`))
+//
+// The functions take the text last, so they also work at the end of a
+// pipeline: {{.Name | truncate 10 | padRight 10}}. Text arguments may be of
+// any type and are converted with fmt.Sprint, so numbers can be padded too.
+package funcmap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unilibs/uniwidth"
+	"github.com/unilibs/uniwidth/internal/layout"
+)
+
+// Ellipsis is appended by truncate when it shortens text.
+const Ellipsis = "…"
+
+// New returns the width functions, measuring text with uniwidth.StringWidth,
+// or with uniwidth.StringWidthWithOptions if opts are given:
+//
+//	width s          display width of s
+//	padRight n s     s padded with spaces on the right to n columns
+//	padLeft n s      s padded with spaces on the left to n columns
+//	center n s       s centered in n columns (the extra space goes right)
+//	truncate n s     s cut to at most n columns, ending in "…" if cut
+//	wrap n s         s word-wrapped to lines of at most n columns
+//
+// Padding never shortens text that is already wider than n. truncate and
+// wrap cut only at cluster boundaries, so emoji sequences and combining
+// marks stay whole.
+//
+// The result is a plain map, so it can be passed to both
+// text/template.Template.Funcs and html/template.Template.Funcs.
+//
+// Example:
+//
+//	funcs := funcmap.New(uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide))
+//	tmpl := template.Must(template.New("t").Funcs(funcs).Parse(`{{center 10 .}}`))
+func New(opts ...uniwidth.Option) map[string]any {
+	m := layout.Measure(opts)
+	return map[string]any{
+		"width": func(s any) int {
+			return m.Width(text(s))
+		},
+		"padRight": func(width int, s any) string {
+			return m.PadRight(text(s), width)
+		},
+		"padLeft": func(width int, s any) string {
+			return m.PadLeft(text(s), width)
+		},
+		"center": func(width int, s any) string {
+			return m.Center(text(s), width)
+		},
+		"truncate": func(width int, s any) string {
+			return m.Truncate(text(s), width, Ellipsis)
+		},
+		"wrap": func(width int, s any) string {
+			return strings.Join(m.Wrap(text(s), width), "\n")
+		},
+	}
+}
+
+// text returns the text a template prints for v.
+func text(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,111 @@
+package funcmap
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/unilibs/uniwidth"
+)
+
+// execute runs tmpl with the functions of New(opts...) on data.
+func execute(t *testing.T, tmpl string, data any, opts ...uniwidth.Option) string {
+	t.Helper()
+	parsed, err := template.New("test").Funcs(New(opts...)).Parse(tmpl)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", tmpl, err)
+	}
+	var b strings.Builder
+	if err := parsed.Execute(&b, data); err != nil {
+		t.Fatalf("Execute(%q) error = %v", tmpl, err)
+	}
+	return b.String()
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		data any
+		want string
+	}{
+		{"width ASCII", `{{width .}}`, "hello", "5"},
+		{"width CJK", `{{width .}}`, "世界", "4"},
+		{"width emoji sequence", `{{width .}}`, "👨‍👩‍👧", "2"},
+		{"width number", `{{width .}}`, 1234, "4"},
+		{"padRight", `|{{padRight 6 .}}|`, "世界", "|世界  |"},
+		{"padLeft", `|{{padLeft 6 .}}|`, "世界", "|  世界|"},
+		{"padLeft number", `|{{padLeft 6 .}}|`, 42, "|    42|"},
+		{"center", `|{{center 7 .}}|`, "世界", "| 世界  |"},
+		{"pad too wide", `|{{padRight 3 .}}|`, "世界", "|世界|"},
+		{"pad combining marks", `|{{padRight 6 .}}|`, "café", "|café  |"},
+		{"truncate", `{{truncate 5 .}}`, "世界世界", "世界…"},
+		{"truncate fits", `{{truncate 8 .}}`, "世界世界", "世界世界"},
+		{"truncate keeps clusters", `{{truncate 3 .}}`, "👨‍👩‍👧 family", "👨‍👩‍👧…"},
+		{"wrap", `{{wrap 10 .}}`, "the quick brown fox", "the quick\nbrown fox"},
+		{"wrap CJK", `{{wrap 5 .}}`, "世界世界世界", "世界\n世界\n世界"},
+		{"wrap zero width", `{{wrap 0 .}}`, "ab 世", "a\nb\n世"},
+		{"wrap negative width", `{{wrap -1 .}}`, "ab 世", "a\nb\n世"},
+		{"pipeline", `|{{. | truncate 5 | padRight 6}}|`, "世界世界", "|世界… |"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := execute(t, tt.tmpl, tt.data); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_Columns(t *testing.T) {
+	rows := []struct{ Name, Size string }{
+		{"報告書.pdf", "1.2 MB"},
+		{"notes 📝.txt", "4 KB"},
+		{"café.txt", "12 KB"},
+	}
+	got := execute(t, `{{range .}}{{padRight 14 .Name}}{{padLeft 7 .Size}}{{"\n"}}{{end}}`, rows)
+
+	want := "" +
+		"報告書.pdf     1.2 MB\n" +
+		"notes 📝.txt     4 KB\n" +
+		"café.txt        12 KB\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if w := uniwidth.StringWidth(line); w != 21 {
+			t.Errorf("line %q is %d columns wide, want 21", line, w)
+		}
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	wide := uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide)
+
+	if got, want := execute(t, `{{width .}}`, "±", wide), "2"; got != want {
+		t.Errorf("width with EAWide = %q, want %q", got, want)
+	}
+	if got, want := execute(t, `|{{padRight 4 .}}|`, "±", wide), "|±  |"; got != want {
+		t.Errorf("padRight with EAWide = %q, want %q", got, want)
+	}
+	// The ellipsis is ambiguous too, so it takes two columns.
+	if got, want := execute(t, `{{truncate 6 .}}`, "hello world", wide), "hell…"; got != want {
+		t.Errorf("truncate with EAWide = %q, want %q", got, want)
+	}
+}
+
+func TestNew_HTMLTemplate(t *testing.T) {
+	tmpl := htmltemplate.Must(htmltemplate.New("test").Funcs(New()).Parse(
+		`<pre>{{padRight 8 .}}|</pre>`))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, "<世界>"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	// Text is padded before it is escaped.
+	if got, want := b.String(), "<pre>&lt;世界&gt;  |</pre>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
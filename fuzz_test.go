@@ -291,3 +291,251 @@ func FuzzIsASCIIOnly(f *testing.F) {
 		// No panics allowed!
 	})
 }
+
+// FuzzASCIIImpls checks that the SIMD and SWAR implementations of the ASCII
+// fast path agree with each other and with a byte-at-a-time loop.
+func FuzzASCIIImpls(f *testing.F) {
+	seeds := []string{
+		"",
+		"Hello, World!",
+		"The quick brown fox jumps over the lazy dog",
+		"line one\tcolumn\nline two\x7f\x00 and a longer tail of ASCII text",
+		"ASCII text long enough for a SIMD block, then 世界",
+		"\x00\x01\x02\x1f\x7f\x80\xff",
+	}
+
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		checkASCIIImpls(t, s)
+	})
+}
+
+// FuzzEscape checks that Escape produces text exactly as wide as the original
+// measures under each visible control policy.
+func FuzzEscape(f *testing.F) {
+	seeds := []string{
+		"",
+		"a\x01b",
+		"tab\there\r\n",
+		"世界\x7f\u0085",
+		"👍\x01🏽",
+		"e\n\u0301",
+		"\xc2\x85\xc2",
+	}
+
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, policy := range []ControlPolicy{ControlCaret, ControlHex, ControlReplacement} {
+			want := StringWidthWithOptions(s, WithControlPolicy(policy))
+			if got := StringWidthWithOptions(Escape(s, policy)); got != want {
+				t.Errorf("width of Escape(%q, %d) = %d, want %d", s, policy, got, want)
+			}
+		}
+	})
+}
+
+// FuzzSanitize checks that Sanitize leaves no controls, bidi controls or
+// invalid UTF-8, and that sanitized text is left unchanged.
+func FuzzSanitize(f *testing.F) {
+	seeds := []string{
+		"",
+		"\x1b[31mred\x1b[0m",
+		"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x07",
+		"\u009b2J\u009d0;t\u009c",
+		"evil\u202etxt.exe",
+		"a\xff\xed\xa0\x80",
+		"\U0001F3F4\U000E0067\U000E0062\U000E007F",
+		"\U0001F3F4\x01\U000E0067\U000E007F",
+	}
+
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, policy := range []ControlPolicy{ControlZero, ControlCaret, ControlHex, ControlReplacement} {
+			checkSanitized(t, s, Sanitize(s, policy), policy)
+		}
+	})
+}
+
+// FuzzLimitExtenders checks that LimitExtenders keeps the cluster widths of
+// the scan with the limit applied, and that limited text is left unchanged.
+func FuzzLimitExtenders(f *testing.F) {
+	seeds := []struct {
+		s     string
+		limit int
+	}{
+		{"", 1},
+		{"Z̶̶̶̶", 2},
+		{"👨‍👩‍👧‍👦", 2},
+		{"👨‍́́👩", 1},
+		{"👍́́🏽", 1},
+		{"ᄀ́ᅡ", 1},
+		{"🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F", 3},
+	}
+
+	for _, seed := range seeds {
+		f.Add(seed.s, seed.limit)
+	}
+
+	f.Fuzz(func(t *testing.T, s string, limit int) {
+		checkLimited(t, s, LimitExtenders(s, limit), limit)
+	})
+}
+
+// FuzzSlice checks that Slice fills its column window exactly, up to the
+// end of the text.
+func FuzzSlice(f *testing.F) {
+	seeds := []struct {
+		s          string
+		start, end int
+	}{
+		{"hello world", 2, 7},
+		{"ab世界cd", 3, 7},
+		{"世界世", 1, 5},
+		{"a👨‍👩‍👧b", 2, 4},
+		{"🇯🇵🇺🇸🇫", 1, 4},
+		{"é́x\x01", 0, 1},
+	}
+
+	for _, seed := range seeds {
+		f.Add(seed.s, seed.start, seed.end)
+	}
+
+	f.Fuzz(func(t *testing.T, s string, start, end int) {
+		checkSlice(t, s, start, end, Slice(s, start, end))
+	})
+}
+
+// FuzzBoundaries checks that NextBoundary and PrevBoundary find the clusters
+// of Clusters from every byte offset.
+func FuzzBoundaries(f *testing.F) {
+	seeds := []string{
+		"",
+		"éx世",
+		"👨‍👩‍👧‍👦👍🏽",
+		"🇯🇵🇺🇸🇫",
+		"1️⃣👨‍©",
+		"각각",
+		"a\r\n\xff\xe4\xb8",
+	}
+
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		checkBoundaries(t, s)
+	})
+}
+
+// FuzzRenderedWidth checks that a Renderer given the output in two pieces
+// measures it as RenderedWidth does.
+func FuzzRenderedWidth(f *testing.F) {
+	seeds := []struct {
+		s     string
+		split int
+	}{
+		{"downloading... 10%\r\x1b[Kdone", 20},
+		{"世界\bx\x1b[3C", 3},
+		{"🇯🇵\r👨‍👩‍👧", 4},
+		{"1️⃣\t\x1b]0;title\x07", 5},
+		{"abcdef\x1b[3D\x1b[1K", 9},
+	}
+
+	for _, seed := range seeds {
+		f.Add(seed.s, seed.split)
+	}
+
+	f.Fuzz(func(t *testing.T, s string, split int) {
+		if completeRunes(s) < len(s) {
+			return // a Renderer waits for the rest of the rune
+		}
+		split = min(max(split, 0), len(s))
+
+		var r Renderer
+		r.WriteString(s[:split])
+		r.WriteString(s[split:])
+		if got, want := r.Width(), RenderedWidth(s); got != want {
+			t.Errorf("Renderer width of %q + %q = %d, RenderedWidth = %d", s[:split], s[split:], got, want)
+		}
+	})
+}
+
+// FuzzBuilder checks that a Builder written in two pieces measures its string
+// as StringWidth does, and that WriteTruncated keeps within the limit.
+func FuzzBuilder(f *testing.F) {
+	seeds := []struct {
+		s     string
+		split int
+		limit int
+	}{
+		{"✅ build passed in 1.2s", 10, 20},
+		{"👨‍👩‍👧x", 4, 3},
+		{"🇯🇵🇺", 4, 3},
+		{"é̂世界", 1, 2},
+		{"a\xe4\xb8\x96\xff", 2, 1},
+	}
+
+	for _, seed := range seeds {
+		f.Add(seed.s, seed.split, seed.limit)
+	}
+
+	f.Fuzz(func(t *testing.T, s string, split, limit int) {
+		split = min(max(split, 0), len(s))
+
+		var b Builder
+		b.WriteString(s[:split])
+		b.WriteString(s[split:])
+		if got, want := b.Width(), StringWidth(s); got != want {
+			t.Errorf("Builder width of %q + %q = %d, StringWidth = %d", s[:split], s[split:], got, want)
+		}
+
+		lb := NewBuilder(limit)
+		lb.WriteTruncated(s[:split], "…")
+		lb.WriteTruncated(s[split:], "…")
+		if lb.Width() > max(limit, 0) {
+			t.Errorf("WriteTruncated(%q), WriteTruncated(%q) with limit %d: width %d", s[:split], s[split:], limit, lb.Width())
+		}
+		if got, want := lb.Width(), StringWidth(lb.String()); got != want {
+			t.Errorf("Builder width of %q = %d, StringWidth = %d", lb.String(), got, want)
+		}
+	})
+}
+
+// FuzzLineIndex checks that an index edited with Replace answers every
+// query as a fresh index of the edited text does: both are checked against
+// measuring the text from scratch.
+func FuzzLineIndex(f *testing.F) {
+	seeds := []struct {
+		s          string
+		start, end int
+		text       string
+	}{
+		{"package main\n\n// 世界 👋\n", 17, 17, "hello, "},
+		{"👨\n👩", 4, 5, "\u200D"},
+		{"ab\r\ncd", 3, 3, "x"},
+		{"e\u2028x", 1, 1, "\u0301"},
+		{"🇯🇵🇺🇸", 4, 8, ""},
+		{"a\xe4\xb8\x96\xff", 2, 3, "\n"},
+	}
+
+	for _, seed := range seeds {
+		f.Add(seed.s, seed.start, seed.end, seed.text)
+	}
+
+	f.Fuzz(func(t *testing.T, s string, start, end int, text string) {
+		x := NewLineIndex(s)
+		checkLineIndex(t, x)
+		x.Replace(start, end, text)
+		checkLineIndex(t, x)
+		checkLineIndex(t, NewLineIndex(x.String()))
+	})
+}
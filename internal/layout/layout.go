@@ -0,0 +1,156 @@
+// Package layout measures, cuts, truncates, wraps and pads text in display
+// columns. It is shared by the table and funcmap packages.
+package layout
+
+import (
+	"strings"
+
+	"github.com/unilibs/uniwidth"
+)
+
+// Measure measures text with uniwidth: with StringWidth when it is empty,
+// and with StringWidthWithOptions and its options otherwise.
+type Measure []uniwidth.Option
+
+// Width returns the display width of s.
+func (m Measure) Width(s string) int {
+	if len(m) == 0 {
+		return uniwidth.StringWidth(s)
+	}
+	return uniwidth.StringWidthWithOptions(s, m...)
+}
+
+// Cut splits s at the last cluster boundary that keeps the head within
+// width columns, and returns the head, its width and the rest of s.
+func (m Measure) Cut(s string, width int) (head string, headWidth int, rest string) {
+	n := 0
+	for cluster, w := range uniwidth.Clusters(s, m...) {
+		if headWidth+w > width {
+			break
+		}
+		n += len(cluster)
+		headWidth += w
+	}
+	return s[:n], headWidth, s[n:]
+}
+
+// Truncate shortens s to at most width columns, cutting at a cluster
+// boundary and ending with ellipsis when anything was cut. If even the
+// ellipsis does not fit, s is cut without it.
+func (m Measure) Truncate(s string, width int, ellipsis string) string {
+	if m.Width(s) <= width {
+		return s
+	}
+	ew := m.Width(ellipsis)
+	if ew > width {
+		head, _, _ := m.Cut(s, width)
+		return head
+	}
+	head, _, _ := m.Cut(s, width-ew)
+	return head + ellipsis
+}
+
+// Wrap breaks s into lines of at most width columns. Lines break at spaces
+// where possible; words wider than a line are broken at cluster boundaries.
+// Explicit line breaks in s are kept. An empty s yields one empty line.
+//
+// A single cluster wider than width gets a line of its own that is still
+// too wide; callers truncate it if they must. A width below 1 is treated
+// as 1, putting each cluster on a line of its own.
+func (m Measure) Wrap(s string, width int) []string {
+	width = max(width, 1)
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		lines = append(lines, m.wrapParagraph(para, width)...)
+	}
+	return lines
+}
+
+// wrapParagraph wraps a single line of text; see Wrap.
+func (m Measure) wrapParagraph(s string, width int) []string {
+	var (
+		lines     []string
+		line      strings.Builder
+		lineWidth int
+	)
+	flush := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		lineWidth = 0
+	}
+
+	for _, word := range strings.Fields(s) {
+		ww := m.Width(word)
+
+		// Keep the word on the current line if it fits after a space.
+		if line.Len() > 0 && lineWidth+1+ww <= width {
+			line.WriteByte(' ')
+			line.WriteString(word)
+			lineWidth += 1 + ww
+			continue
+		}
+		if line.Len() > 0 {
+			flush()
+		}
+
+		// Break words that are wider than a whole line.
+		for ww > width {
+			head, hw, rest := m.Cut(word, width)
+			if head == "" {
+				// A single cluster wider than the line: give it a line of
+				// its own.
+				head, rest = m.firstCluster(word)
+				hw = m.Width(head)
+			}
+			line.WriteString(head)
+			lineWidth = hw
+			flush()
+			word, ww = rest, ww-hw
+		}
+		if word != "" {
+			line.WriteString(word)
+			lineWidth = ww
+		}
+	}
+
+	if line.Len() > 0 || len(lines) == 0 {
+		flush()
+	}
+	return lines
+}
+
+// firstCluster splits off the first cluster of s.
+func (m Measure) firstCluster(s string) (head, rest string) {
+	for cluster := range uniwidth.Clusters(s, m...) {
+		return cluster, s[len(cluster):]
+	}
+	return "", s
+}
+
+// PadRight pads s with spaces on the right to width columns.
+// Text that is already wide enough is unchanged.
+func (m Measure) PadRight(s string, width int) string {
+	return s + spaces(width-m.Width(s))
+}
+
+// PadLeft pads s with spaces on the left to width columns.
+// Text that is already wide enough is unchanged.
+func (m Measure) PadLeft(s string, width int) string {
+	return spaces(width-m.Width(s)) + s
+}
+
+// Center pads s with spaces on both sides to width columns; when the
+// padding is odd, the extra space goes to the right. Text that is already
+// wide enough is unchanged.
+func (m Measure) Center(s string, width int) string {
+	gap := width - m.Width(s)
+	return spaces(gap/2) + s + spaces(gap-gap/2)
+}
+
+// spaces returns n spaces, or "" if n is not positive.
+func spaces(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat(" ", n)
+}
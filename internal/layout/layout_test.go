@@ -0,0 +1,116 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/unilibs/uniwidth"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		width    int
+		ellipsis string
+		want     string
+	}{
+		{"fits", "hello", 5, "…", "hello"},
+		{"ASCII", "hello world", 8, "…", "hello w…"},
+		{"CJK on boundary", "世界世界", 5, "…", "世界…"},
+		{"CJK off boundary", "世界世界", 6, "…", "世界…"},
+		{"emoji sequence kept whole", "👨‍👩‍👧 family", 3, "…", "👨‍👩‍👧…"},
+		{"combining mark kept", "ééé", 2, "…", "é…"},
+		{"multi-column ellipsis", "hello world", 8, "...", "hello..."},
+		{"no ellipsis", "hello world", 5, "", "hello"},
+		{"ellipsis wider than width", "hello", 2, "...", "he"},
+		{"zero width", "hello", 0, "…", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Measure
+			got := m.Truncate(tt.s, tt.width, tt.ellipsis)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.s, tt.width, tt.ellipsis, got, tt.want)
+			}
+			if w := m.Width(got); w > tt.width {
+				t.Errorf("Truncate(%q, %d, %q) is %d columns wide", tt.s, tt.width, tt.ellipsis, w)
+			}
+		})
+	}
+}
+
+func TestTruncate_EastAsianWide(t *testing.T) {
+	m := Measure{uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide)}
+
+	// The ellipsis is ambiguous, so it takes two columns.
+	if got, want := m.Truncate("hello world", 6, "…"), "hell…"; got != want {
+		t.Errorf("Truncate = %q, want %q", got, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  []string
+	}{
+		{"fits", "hello", 10, []string{"hello"}},
+		{"empty", "", 10, []string{""}},
+		{"words", "the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"collapses spaces", "a   b", 10, []string{"a b"}},
+		{"long word", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"long word after short", "a abcdefg", 4, []string{"a", "abcd", "efg"}},
+		{"CJK", "世界世界世界", 5, []string{"世界", "世界", "世界"}},
+		{"emoji sequences", "👍🏽👍🏽👍🏽", 4, []string{"👍🏽👍🏽", "👍🏽"}},
+		{"cluster wider than line", "世a", 1, []string{"世", "a"}},
+		{"line breaks", "ab\ncd ef", 5, []string{"ab", "cd ef"}},
+		{"blank line", "ab\n\ncd", 5, []string{"ab", "", "cd"}},
+		{"zero width", "ab 世", 0, []string{"a", "b", "世"}},
+		{"negative width", "ab 世", -1, []string{"a", "b", "世"}},
+		{"negative width empty", "", -1, []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Measure
+			if got := m.Wrap(tt.s, tt.width); !slices.Equal(got, tt.want) {
+				t.Errorf("Wrap(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	var m Measure
+	tests := []struct {
+		name  string
+		pad   func(string, int) string
+		s     string
+		width int
+		want  string
+	}{
+		{"PadRight", m.PadRight, "世界", 6, "世界  "},
+		{"PadLeft", m.PadLeft, "世界", 6, "  世界"},
+		{"Center", m.Center, "世界", 7, " 世界  "},
+		{"Center even", m.Center, "世界", 6, " 世界 "},
+		{"PadLeft exact", m.PadLeft, "世界", 4, "世界"},
+		{"PadLeft too wide", m.PadLeft, "世界", 3, "世界"},
+		{"PadRight too wide", m.PadRight, "世界", 3, "世界"},
+		{"Center too wide", m.Center, "世界", 3, "世界"},
+		{"PadRight emoji", m.PadRight, "👨‍👩‍👧", 4, "👨‍👩‍👧  "},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pad(tt.s, tt.width); got != tt.want {
+			t.Errorf("%s(%q, %d) = %q, want %q", tt.name, tt.s, tt.width, got, tt.want)
+		}
+	}
+
+	wide := Measure{uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide)}
+	if got, want := wide.PadRight("±", 3), "± "; got != want {
+		t.Errorf("PadRight(%q, 3) with EAWide = %q, want %q", "±", got, want)
+	}
+}
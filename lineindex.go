@@ -0,0 +1,517 @@
+package uniwidth
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// lineIndexChunk is the distance in bytes between the checkpoints of a
+// LineIndex within a line, and so the most text a query measures.
+const lineIndexChunk = 256
+
+// lineIndexBlock is the size in bytes of the blocks of whole lines a
+// LineIndex splits its document into. A block grows to twice this before it
+// is split and is merged with a neighbor below half of it.
+const lineIndexBlock = 4096
+
+// LineIndex indexes the columns of a document, for editors that map between
+// byte offsets and screen columns on every keystroke without measuring whole
+// lines again.
+//
+// It keeps a checkpoint at the start of every line and at a cluster boundary
+// every 256 bytes within long lines, each with its line and column, and the
+// width of every line. The document is split into blocks of whole lines of
+// about 4KB, each with its own checkpoints and widths, under a tree of their
+// lengths, line counts and widest lines. Position, Offset and LineWidth find
+// the block in the tree and the nearest checkpoint by binary search, and
+// measure at most the text up to the next one: O(log n). Width is O(1).
+//
+// Replace, Insert and Delete copy only the blocks the edit touches and
+// measure the edited text again from the last checkpoint before it until the
+// clusters line up with an old checkpoint after it; the rest of the block is
+// shifted and the other blocks are left alone. A line longer than a block is
+// never split, so an edit copies all of its text and checkpoints. When an
+// edit splits or merges blocks, the tree is rebuilt, in time proportional to
+// the number of blocks.
+//
+// Lines end at LF, CR LF, a lone CR, U+2028 or U+2029, as in Lines, but a
+// line break at the end of the document starts an empty last line, where
+// the cursor can go; an empty document has one empty line. Lines are
+// measured as StringWidth measures them, or with options as
+// StringWidthWithOptions does.
+//
+// Example:
+//
+//	x := uniwidth.NewLineIndex("package main\n\n// 世界 👋\n")
+//	x.Position(18)  // 2, 3 (after "// ", before 世)
+//	x.Offset(2, 5)  // 20 (界)
+//	x.Insert(17, "hello, ")
+//	x.LineWidth(2)  // 17
+type LineIndex struct {
+	o      *Options
+	blocks []*lineBlock // never empty
+	sums   []lineSum    // tree over blocks: sums[1] is the root, block i is at sums[len(sums)/2+i]
+}
+
+// lineBlock is a run of whole lines of a LineIndex. Every block but the last
+// ends with a line break, and the next block starts the line after it. Blocks
+// are never modified; an edit replaces them.
+type lineBlock struct {
+	s      string
+	points []linePoint // in order of offset, relative to the block; points[0] is at offset 0
+	widths []int       // width of each line; after a final line break, 0 for the next line
+	max    int         // the largest of widths
+}
+
+// linePoint is a checkpoint of a LineIndex: a cluster boundary at offset,
+// col columns into its line.
+type linePoint struct {
+	offset, line, col int
+}
+
+// lineSum sums up a run of blocks: their length, their line breaks and the
+// width of their widest line.
+type lineSum struct {
+	bytes, breaks, max int
+}
+
+// NewLineIndex indexes s, measuring it with opts.
+func NewLineIndex(s string, opts ...Option) *LineIndex {
+	o := clusterOptions(opts)
+	b := &lineBlock{s: s, points: []linePoint{{}}}
+	b.remeasure(o, 0, 0, nil, 0, nil)
+	x := &LineIndex{o: o, blocks: b.split()}
+	x.build()
+	return x
+}
+
+// String returns the document. It takes time proportional to its length.
+func (x *LineIndex) String() string {
+	if len(x.blocks) == 1 {
+		return x.blocks[0].s
+	}
+	var b strings.Builder
+	b.Grow(x.Len())
+	for _, block := range x.blocks {
+		b.WriteString(block.s)
+	}
+	return b.String()
+}
+
+// Len returns the length of the document in bytes.
+func (x *LineIndex) Len() int {
+	return x.sums[1].bytes
+}
+
+// LineCount returns the number of lines.
+func (x *LineIndex) LineCount() int {
+	return x.sums[1].breaks + 1
+}
+
+// LineWidth returns the width of line (counting from 0), or 0 if there is
+// no such line.
+func (x *LineIndex) LineWidth(line int) int {
+	if line < 0 || line >= x.LineCount() {
+		return 0
+	}
+	i, _, first := x.blockOfLine(line)
+	return x.blocks[i].widths[line-first]
+}
+
+// Width returns the width of the widest line.
+func (x *LineIndex) Width() int {
+	return x.sums[1].max
+}
+
+// Position returns the line and column of byte offset, counting from 0. An
+// offset inside a cluster gives the column where the cluster starts, and an
+// offset in a line break the width of its line. Offsets outside the
+// document are clamped to it.
+func (x *LineIndex) Position(offset int) (line, col int) {
+	offset = min(max(offset, 0), x.Len())
+	i, start, first := x.blockAt(offset)
+	line, col = x.blocks[i].position(x.o, offset-start)
+	return first + line, col
+}
+
+// Offset returns the byte offset of the cluster at column col of line,
+// counting from 0: the cluster that covers the column, so a wide character
+// is found from either of its columns. A column past the end of the line
+// gives the offset of its end (before the line break). A line or column
+// outside the document is clamped to it.
+func (x *LineIndex) Offset(line, col int) int {
+	line = min(max(line, 0), x.LineCount()-1)
+	i, start, first := x.blockOfLine(line)
+	return start + x.blocks[i].offset(x.o, line-first, max(col, 0))
+}
+
+// Insert inserts text at byte offset and updates the index.
+func (x *LineIndex) Insert(offset int, text string) {
+	x.Replace(offset, offset, text)
+}
+
+// Delete deletes the bytes [start, end) and updates the index.
+func (x *LineIndex) Delete(start, end int) {
+	x.Replace(start, end, "")
+}
+
+// Replace replaces the bytes [start, end) with text and updates the index.
+// Offsets outside the document are clamped to it.
+func (x *LineIndex) Replace(start, end int, text string) {
+	start = min(max(start, 0), x.Len())
+	end = min(max(end, start), x.Len())
+
+	// The blocks the edit changes: from the block of start, or the one
+	// before it if the edit is at its start (where text can join a CR
+	// ending that block), to the block of end, or the one after it if the
+	// edit reaches into the line break ending the block. The text after
+	// them is unchanged and starts a line, so they are measured on their
+	// own.
+	first, from, _ := x.blockAt(start)
+	if first > 0 && start == from {
+		first--
+		from -= len(x.blocks[first].s)
+	}
+	last, to, _ := x.blockAt(end)
+	to += len(x.blocks[last].s)
+	if last+1 < len(x.blocks) && end > to-utf8.UTFMax {
+		last++
+	}
+
+	b := x.blocks[first]
+	for _, next := range x.blocks[first+1 : last+1] {
+		b = b.join(next)
+	}
+	b = b.replace(x.o, start-from, end-from, text)
+
+	// Keep blocks from getting small, so that there are not many more of
+	// them than the document needs.
+	if len(b.s) < lineIndexBlock/2 && last+1 < len(x.blocks) {
+		last++
+		b = b.join(x.blocks[last])
+	} else if len(b.s) < lineIndexBlock/2 && first > 0 {
+		first--
+		b = x.blocks[first].join(b)
+	}
+
+	blocks := b.split()
+	if len(blocks) == last+1-first {
+		copy(x.blocks[first:], blocks)
+		for i := first; i <= last; i++ {
+			x.update(i)
+		}
+		return
+	}
+	x.blocks = slices.Replace(x.blocks, first, last+1, blocks...)
+	x.build()
+}
+
+// build builds the tree over the blocks.
+func (x *LineIndex) build() {
+	n := 1
+	for n < len(x.blocks) {
+		n *= 2
+	}
+	x.sums = make([]lineSum, 2*n)
+	for i, b := range x.blocks {
+		x.sums[n+i] = b.sum()
+	}
+	for i := n - 1; i > 0; i-- {
+		x.sums[i] = x.sums[2*i].add(x.sums[2*i+1])
+	}
+}
+
+// update updates the tree after block i has been replaced.
+func (x *LineIndex) update(i int) {
+	j := len(x.sums)/2 + i
+	x.sums[j] = x.blocks[i].sum()
+	for j /= 2; j > 0; j /= 2 {
+		x.sums[j] = x.sums[2*j].add(x.sums[2*j+1])
+	}
+}
+
+// blockAt returns the block containing byte offset, with the offset and
+// line where it starts. An offset between two blocks is in the second; the
+// end of the document is in the last block.
+func (x *LineIndex) blockAt(offset int) (i, start, line int) {
+	if offset >= x.Len() {
+		i = len(x.blocks) - 1
+		return i, x.Len() - len(x.blocks[i].s), x.sums[1].breaks - x.blocks[i].breaks()
+	}
+	j := 1
+	for j < len(x.sums)/2 {
+		if left := x.sums[2*j]; offset < left.bytes {
+			j = 2 * j
+		} else {
+			offset -= left.bytes
+			start += left.bytes
+			line += left.breaks
+			j = 2*j + 1
+		}
+	}
+	return j - len(x.sums)/2, start, line
+}
+
+// blockOfLine returns the block containing line, with the offset and line
+// where it starts.
+func (x *LineIndex) blockOfLine(line int) (i, start, first int) {
+	if line >= x.sums[1].breaks {
+		i = len(x.blocks) - 1
+		return i, x.Len() - len(x.blocks[i].s), x.sums[1].breaks - x.blocks[i].breaks()
+	}
+	j := 1
+	for j < len(x.sums)/2 {
+		if left := x.sums[2*j]; line < left.breaks {
+			j = 2 * j
+		} else {
+			line -= left.breaks
+			start += left.bytes
+			first += left.breaks
+			j = 2*j + 1
+		}
+	}
+	return j - len(x.sums)/2, start, first
+}
+
+// add returns the sum of a run of blocks followed by another.
+func (s lineSum) add(t lineSum) lineSum {
+	return lineSum{s.bytes + t.bytes, s.breaks + t.breaks, max(s.max, t.max)}
+}
+
+// sum returns the sum of b on its own.
+func (b *lineBlock) sum() lineSum {
+	return lineSum{len(b.s), b.breaks(), b.max}
+}
+
+// breaks returns the number of line breaks in b.
+func (b *lineBlock) breaks() int {
+	return len(b.widths) - 1
+}
+
+// position returns the line and column of byte offset in b.
+func (b *lineBlock) position(o *Options, offset int) (line, col int) {
+	k, _ := slices.BinarySearchFunc(b.points, offset+1, func(p linePoint, offset int) int {
+		return p.offset - offset
+	})
+	p, end := b.points[k-1], b.segmentEnd(k-1)
+
+	i, col := p.offset, p.col
+	for i < offset && lineBreakAt(b.s[i:end]) == 0 {
+		n, w := b.cluster(o, i, end)
+		if i+n > offset {
+			break
+		}
+		i += n
+		col += w
+	}
+	return p.line, col
+}
+
+// offset returns the byte offset in b of the cluster at column col of line,
+// which is a line of b.
+func (b *lineBlock) offset(o *Options, line, col int) int {
+	// The checkpoints of the line, and the last one at or before col.
+	first, _ := slices.BinarySearchFunc(b.points, line, func(p linePoint, line int) int {
+		return p.line - line
+	})
+	last, _ := slices.BinarySearchFunc(b.points, line+1, func(p linePoint, line int) int {
+		return p.line - line
+	})
+	k, _ := slices.BinarySearchFunc(b.points[first:last], col+1, func(p linePoint, col int) int {
+		return p.col - col
+	})
+	k += first - 1
+	p, end := b.points[k], b.segmentEnd(k)
+
+	i, c := p.offset, p.col
+	for i < end && lineBreakAt(b.s[i:end]) == 0 {
+		n, w := b.cluster(o, i, end)
+		if c+w > col {
+			break
+		}
+		i += n
+		c += w
+	}
+	return i
+}
+
+// replace returns b with the bytes [start, end) replaced by text.
+func (b *lineBlock) replace(o *Options, start, end int, text string) *lineBlock {
+	nb := &lineBlock{s: b.s[:start] + text + b.s[end:], points: b.points}
+
+	// Restart at the last checkpoint before the edit that the edit cannot
+	// move: the text before it and the rune at it are unchanged, so it is
+	// still a cluster boundary with the same line and column.
+	k, _ := slices.BinarySearchFunc(b.points, start-utf8.UTFMax+1, func(p linePoint, offset int) int {
+		return p.offset - offset
+	})
+	k = max(k-1, 0)
+
+	// The old checkpoints after the edit, where measuring can stop.
+	j, _ := slices.BinarySearchFunc(b.points, end, func(p linePoint, offset int) int {
+		return p.offset - offset
+	})
+	nb.remeasure(o, k, start+len(text), b.points[j:], len(text)-(end-start), b.widths)
+	return nb
+}
+
+// remeasure measures the text from checkpoint k and replaces the checkpoints
+// after it and the widths of the lines from its line on.
+//
+// Measuring stops early at the first cluster boundary at or after editEnd
+// that is one of the old checkpoints tail, shifted by delta bytes: from
+// there on the text is unchanged, so the rest of tail and of the old line
+// widths is shifted and reused.
+func (b *lineBlock) remeasure(o *Options, k, editEnd int, tail []linePoint, delta int, widths []int) {
+	p := b.points[k]
+	var (
+		points    []linePoint // new checkpoints after p
+		newWidths []int       // widths of the lines from p.line on
+	)
+
+	i, line, col, last := p.offset, p.line, p.col, p.offset
+	lineStart := false
+	for {
+		if i >= editEnd && i > p.offset {
+			for len(tail) > 0 && tail[0].offset+delta < i {
+				tail = tail[1:]
+			}
+			if len(tail) > 0 && tail[0].offset+delta == i {
+				// Reuse the old checkpoints, moved by the change in length,
+				// line count and, on this line, width.
+				q := tail[0]
+				dline, dcol := line-q.line, col-q.col
+				moved := slices.Clone(tail)
+				for t := range moved {
+					if moved[t].line == q.line {
+						moved[t].col += dcol
+					}
+					moved[t].offset += delta
+					moved[t].line += dline
+				}
+				b.points = slices.Concat(b.points[:k+1], points, moved)
+				b.widths = slices.Concat(widths[:p.line], newWidths, []int{widths[q.line] + dcol}, widths[q.line+1:])
+				b.max = slices.Max(b.widths)
+				return
+			}
+		}
+
+		if lineStart || i-last >= lineIndexChunk {
+			points = append(points, linePoint{i, line, col})
+			last = i
+			lineStart = false
+		}
+		if i == len(b.s) {
+			break
+		}
+
+		if n := lineBreakAt(b.s[i:]); n > 0 {
+			newWidths = append(newWidths, col)
+			line++
+			col = 0
+			i += n
+			lineStart = true
+			continue
+		}
+		n, w := b.cluster(o, i, len(b.s))
+		i += n
+		col += w
+	}
+
+	newWidths = append(newWidths, col)
+	b.points = slices.Concat(b.points[:k+1], points)
+	b.widths = slices.Concat(widths[:p.line], newWidths)
+	b.max = slices.Max(b.widths)
+}
+
+// join returns the block of the lines of b followed by those of next. b must
+// end with a line break.
+func (b *lineBlock) join(next *lineBlock) *lineBlock {
+	line := b.breaks()
+	points := make([]linePoint, len(b.points), len(b.points)+len(next.points)-1)
+	copy(points, b.points)
+	for _, p := range next.points[1:] {
+		points = append(points, linePoint{p.offset + len(b.s), p.line + line, p.col})
+	}
+	return &lineBlock{
+		s:      b.s + next.s,
+		points: points,
+		widths: slices.Concat(b.widths[:line], next.widths),
+		max:    max(b.max, next.max),
+	}
+}
+
+// split splits b at line starts into blocks of at least lineIndexBlock
+// bytes, if it is more than twice that. Lines are never split.
+func (b *lineBlock) split() []*lineBlock {
+	if len(b.s) <= 2*lineIndexBlock {
+		return []*lineBlock{b}
+	}
+
+	var blocks []*lineBlock
+	k := 0 // the checkpoint where the next block starts
+	for j := 1; j < len(b.points); j++ {
+		p := b.points[j]
+		if p.line == b.points[j-1].line || p.offset-b.points[k].offset < lineIndexBlock ||
+			len(b.s)-p.offset < lineIndexBlock {
+			continue
+		}
+		blocks = append(blocks, b.slice(k, j))
+		k = j
+	}
+	return append(blocks, b.slice(k, len(b.points)))
+}
+
+// slice returns the lines of b from checkpoint k, a line start, to
+// checkpoint j, a later line start, or to the end of b if j is
+// len(b.points).
+func (b *lineBlock) slice(k, j int) *lineBlock {
+	start, end := b.points[k], linePoint{len(b.s), b.breaks(), 0}
+	points := b.points[k:]
+	if j < len(b.points) {
+		end = b.points[j]
+		points = b.points[k : j+1]
+	}
+
+	moved := make([]linePoint, len(points))
+	for t, p := range points {
+		moved[t] = linePoint{p.offset - start.offset, p.line - start.line, p.col}
+	}
+	widths := slices.Clone(b.widths[start.line:])
+	if j < len(b.points) {
+		widths = slices.Concat(b.widths[start.line:end.line], []int{0})
+	}
+	return &lineBlock{s: b.s[start.offset:end.offset], points: moved, widths: widths, max: slices.Max(widths)}
+}
+
+// segmentEnd returns the offset of the checkpoint after checkpoint k, or the
+// end of the block. No cluster crosses it.
+func (b *lineBlock) segmentEnd(k int) int {
+	if k+1 < len(b.points) {
+		return b.points[k+1].offset
+	}
+	return len(b.s)
+}
+
+// cluster returns the length and width of the cluster at offset i, which
+// ends at or before end and, as lines are measured on their own, before a
+// line break.
+func (b *lineBlock) cluster(o *Options, i, end int) (n, width int) {
+	n, width = nextCluster(b.s[i:end], o)
+
+	// Controls end clusters, but U+2028 and U+2029 are zero-width
+	// extenders.
+	if cut := strings.IndexAny(b.s[i:i+n], lineBreaks); cut > 0 {
+		n, width = nextCluster(b.s[i:i+cut], o)
+	}
+	return n, width
+}
+
+// lineBreakAt returns the length of the line break at the start of s, or 0.
+func lineBreakAt(s string) int {
+	if r := firstRune(s); r == 0 || !strings.ContainsRune(lineBreaks, r) {
+		return 0
+	}
+	return lineBreakLen(s)
+}
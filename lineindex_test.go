@@ -0,0 +1,418 @@
+package uniwidth
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLineIndex(t *testing.T) {
+	x := NewLineIndex("package main\n\n// 世界 👋\n")
+
+	if got := x.LineCount(); got != 4 {
+		t.Errorf("LineCount() = %d, want 4", got)
+	}
+	for line, want := range []int{12, 0, 10, 0} {
+		if got := x.LineWidth(line); got != want {
+			t.Errorf("LineWidth(%d) = %d, want %d", line, got, want)
+		}
+	}
+	if got := x.Width(); got != 12 {
+		t.Errorf("Width() = %d, want 12", got)
+	}
+
+	positions := []struct {
+		offset    int
+		line, col int
+	}{
+		{-1, 0, 0},
+		{0, 0, 0},
+		{12, 0, 12}, // at the LF
+		{13, 1, 0},
+		{14, 2, 0},
+		{17, 2, 3},
+		{18, 2, 3}, // inside 世
+		{20, 2, 5},
+		{23, 2, 7},
+		{24, 2, 8},
+		{26, 2, 8}, // inside 👋
+		{28, 2, 10},
+		{29, 3, 0},
+		{100, 3, 0},
+	}
+	for _, tt := range positions {
+		if line, col := x.Position(tt.offset); line != tt.line || col != tt.col {
+			t.Errorf("Position(%d) = %d, %d, want %d, %d", tt.offset, line, col, tt.line, tt.col)
+		}
+	}
+
+	offsets := []struct {
+		line, col int
+		offset    int
+	}{
+		{0, 0, 0},
+		{0, 4, 4},
+		{0, 99, 12}, // past the end of the line
+		{1, 3, 13},
+		{2, -1, 14},
+		{2, 3, 17},
+		{2, 4, 17}, // second column of 世
+		{2, 5, 20},
+		{2, 8, 24},
+		{2, 9, 24},
+		{2, 10, 28},
+		{3, 0, 29},
+		{-1, 5, 5},
+		{9, 5, 29},
+	}
+	for _, tt := range offsets {
+		if got := x.Offset(tt.line, tt.col); got != tt.offset {
+			t.Errorf("Offset(%d, %d) = %d, want %d", tt.line, tt.col, got, tt.offset)
+		}
+	}
+
+	x.Insert(17, "hello, ")
+	if got, want := x.String(), "package main\n\n// hello, 世界 👋\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := x.LineWidth(2); got != 17 {
+		t.Errorf("LineWidth(2) after Insert = %d, want 17", got)
+	}
+	checkLineIndex(t, x)
+}
+
+func TestLineIndex_Lines(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		widths []int
+	}{
+		{"empty", "", []int{0}},
+		{"one line", "hello", []int{5}},
+		{"trailing LF", "hello\n", []int{5, 0}},
+		{"CR LF", "ab\r\n世界\r\n", []int{2, 4, 0}},
+		{"lone CR", "ab\r世界", []int{2, 4}},
+		{"LF CR", "ab\n\rc", []int{2, 0, 1}},
+		{"separators", "a\u2028bc\u2029", []int{1, 2, 0}},
+		{"separator after mark", "e\u0301\u2028x", []int{1, 1}},
+		{"NEL is not a line break", "a\u0085b", []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := NewLineIndex(tt.s)
+			var got []int
+			for line := range x.LineCount() {
+				got = append(got, x.LineWidth(line))
+			}
+			if !slices.Equal(got, tt.widths) {
+				t.Errorf("line widths of %q = %v, want %v", tt.s, got, tt.widths)
+			}
+			checkLineIndex(t, x)
+		})
+	}
+}
+
+func TestLineIndex_Options(t *testing.T) {
+	x := NewLineIndex("±±\n±", WithEastAsianAmbiguous(EAWide))
+	if got := x.LineWidth(0); got != 4 {
+		t.Errorf("LineWidth(0) = %d, want 4", got)
+	}
+	if line, col := x.Position(2); line != 0 || col != 2 {
+		t.Errorf("Position(2) = %d, %d, want 0, 2", line, col)
+	}
+	if got := x.Offset(0, 3); got != 2 {
+		t.Errorf("Offset(0, 3) = %d, want 2", got)
+	}
+
+	x.Insert(x.Len(), "±")
+	if got := x.LineWidth(1); got != 4 {
+		t.Errorf("LineWidth(1) after Insert = %d, want 4", got)
+	}
+	checkLineIndex(t, x, WithEastAsianAmbiguous(EAWide))
+}
+
+func TestLineIndex_Edits(t *testing.T) {
+	long := strings.Repeat("世界 👨\u200D👩\u200D👧 e\u0301 🇯🇵 1\uFE0F\u20E3 ", 40)
+	doc := "first\n" + long + "\n" + long + "\r\nlast"
+
+	tests := []struct {
+		name       string
+		start, end int
+		text       string
+	}{
+		{"insert at start", 0, 0, "x"},
+		{"insert at end", len(doc), len(doc), "x"},
+		{"insert line break", 3, 3, "\n"},
+		{"insert CR before LF", 5, 5, "\r"},
+		{"delete line break", 5, 6, ""},
+		{"delete all", 0, len(doc), ""},
+		{"replace all", 0, len(doc), "a\nb"},
+		{"join emoji", 12, 12, "\u200D"},
+		{"extend cluster", 6, 6, "e"},
+		{"combining mark", 9, 9, "\u0301"},
+		{"split flag", 50, 54, ""},
+		{"keycap", 500, 500, "1\uFE0F"},
+		{"separator", 600, 600, "\u2029"},
+		{"wide to narrow", 6, 9, "ab"},
+		{"middle of long line", 2000, 2100, "世界\n世界"},
+		{"clamped", -5, len(doc) + 5, "x"},
+		{"inside rune", 7, 8, ""},
+		{"split CR LF", len(doc) - 5, len(doc) - 5, "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := NewLineIndex(doc)
+			x.Replace(tt.start, tt.end, tt.text)
+
+			start := min(max(tt.start, 0), len(doc))
+			end := min(max(tt.end, start), len(doc))
+			if got, want := x.String(), doc[:start]+tt.text+doc[end:]; got != want {
+				t.Fatalf("String() = %q, want %q", got, want)
+			}
+			checkLineIndex(t, x)
+		})
+	}
+}
+
+func TestLineIndex_Typing(t *testing.T) {
+	// Type a document a cluster at a time, at the end and in the middle.
+	text := strings.Repeat("hello 世界 👋🏽 e\u0301\n", 30)
+
+	x := NewLineIndex("")
+	y := NewLineIndex("")
+	for cluster := range Clusters(text) {
+		x.Insert(x.Len(), cluster)
+		y.Insert(y.Len()/2, cluster)
+	}
+	checkLineIndex(t, x)
+	checkLineIndex(t, y)
+
+	for x.Len() > 0 {
+		x.Delete(x.Len()/3, x.Len()/3+5)
+	}
+	checkLineIndex(t, x)
+}
+
+func TestLineIndex_Blocks(t *testing.T) {
+	// Lines ending in every kind of line break, some longer than a block.
+	var b strings.Builder
+	for i := range 250 {
+		b.WriteString(strings.Repeat("世界 👋🏽 e\u0301 ", i%5+1))
+		if i == 100 {
+			b.WriteString(strings.Repeat("x", lineIndexBlock+1000))
+		}
+		b.WriteString([]string{"\n", "\r\n", "\r", "\u2028"}[i%4])
+	}
+	doc := b.String()
+
+	x := NewLineIndex(doc)
+	if len(x.blocks) < 3 {
+		t.Fatalf("%d bytes in %d blocks, want at least 3", len(doc), len(x.blocks))
+	}
+	checkLineIndex(t, x)
+
+	// Edit at every block boundary, where lines can join and CR LF form.
+	var edits []struct {
+		start, end int
+		text       string
+	}
+	offset := 0
+	for _, block := range x.blocks[:len(x.blocks)-1] {
+		offset += len(block.s)
+		edits = append(edits, []struct {
+			start, end int
+			text       string
+		}{
+			{offset, offset, "\n"},
+			{offset, offset, "x"},
+			{offset - 1, offset, ""},
+			{offset, offset + 1, ""},
+			{offset - 2, offset + 2, "\u0301"},
+		}...)
+	}
+
+	for _, tt := range edits {
+		y := NewLineIndex(doc)
+		y.Replace(tt.start, tt.end, tt.text)
+		if got, want := y.String(), doc[:tt.start]+tt.text+doc[tt.end:]; got != want {
+			t.Fatalf("Replace(%d, %d, %q): wrong document", tt.start, tt.end, tt.text)
+		}
+		checkLineIndex(t, y)
+	}
+
+	// Delete most of the document, a piece at a time, and type it again.
+	for x.Len() > 100 {
+		x.Delete(x.Len()/4, x.Len()/4+x.Len()/8+1)
+	}
+	checkLineIndex(t, x)
+	for i := 0; i < len(doc); i += 1000 {
+		x.Insert(x.Len()/2, doc[i:min(i+1000, len(doc))])
+	}
+	checkLineIndex(t, x)
+}
+
+func TestLineIndex_StopsEarly(t *testing.T) {
+	// Lines of 2700 bytes, two or three to a block.
+	line := strings.Repeat("世界 👋🏽 e\u0301 ", 150)
+	x := NewLineIndex(strings.Repeat(line+"\n", 40))
+
+	i := len(x.blocks) / 2
+	start, first := 0, 0
+	for _, b := range x.blocks[:i] {
+		start += len(b.s)
+		first += b.breaks()
+	}
+	offset := x.Offset(first, 4)
+
+	// Corrupt the block of the edit after it: the widths of its lines and a
+	// checkpoint further along the edited line. Measuring them again would
+	// repair them; stopping early shifts them as they are.
+	blocks := slices.Clone(x.blocks)
+	c := *x.blocks[i]
+	c.points = slices.Clone(c.points)
+	c.widths = slices.Clone(c.widths)
+	k := 8
+	if c.points[k].line != 0 {
+		t.Fatalf("checkpoint %d is on line %d, want 0", k, c.points[k].line)
+	}
+	c.points[k].col += 1000
+	c.widths[0] += 1000
+	c.widths[1] += 1000
+	x.blocks[i] = &c
+
+	x.Insert(offset, "x")
+
+	for j, b := range x.blocks {
+		if j != i && b != blocks[j] {
+			t.Errorf("block %d was replaced by an edit in block %d", j, i)
+		}
+	}
+	b := x.blocks[i]
+	want := linePoint{c.points[k].offset + 1, 0, c.points[k].col + 1}
+	if j, ok := slices.BinarySearchFunc(b.points, want.offset, func(p linePoint, offset int) int {
+		return p.offset - offset
+	}); !ok || b.points[j] != want {
+		t.Errorf("checkpoints after the edit = %v, want %v among them (shifted, not measured)", b.points, want)
+	}
+	if got, want := b.widths[0], c.widths[0]+1; got != want {
+		t.Errorf("width of the edited line = %d, want %d (shifted, not measured)", got, want)
+	}
+	if got, want := b.widths[1], c.widths[1]; got != want {
+		t.Errorf("width of the next line = %d, want %d (kept, not measured)", got, want)
+	}
+}
+
+// checkLineIndex checks every query of x against measuring its document
+// from scratch, and the blocks of x against its tree.
+func checkLineIndex(t *testing.T, x *LineIndex, opts ...Option) {
+	t.Helper()
+	s := x.String()
+	if x.Len() != len(s) {
+		t.Fatalf("Len() = %d, want %d", x.Len(), len(s))
+	}
+	checkLineBlocks(t, x)
+
+	// The lines of s with their start offsets, and the line, column and
+	// cluster start of every offset.
+	type position struct{ line, col, end, width int }
+	positions := make([]position, len(s)+1)
+	var starts, widths []int
+	for i := 0; ; {
+		starts = append(starts, i)
+		end := len(s)
+		if b := strings.IndexAny(s[i:], lineBreaks); b >= 0 {
+			end = i + b
+		}
+		col := 0
+		for cluster, width := range Clusters(s[i:end], opts...) {
+			for j := i; j < i+len(cluster); j++ {
+				positions[j] = position{len(starts) - 1, col, i + len(cluster), width}
+			}
+			i += len(cluster)
+			col += width
+		}
+		widths = append(widths, col)
+		if end == len(s) {
+			positions[end] = position{line: len(starts) - 1, col: col}
+			break
+		}
+		n := lineBreakLen(s[end:])
+		for j := end; j < end+n; j++ {
+			positions[j] = position{line: len(starts) - 1, col: col}
+		}
+		i = end + n
+		if i == len(s) {
+			starts = append(starts, i)
+			widths = append(widths, 0)
+			positions[i] = position{line: len(starts) - 1}
+			break
+		}
+	}
+
+	if got := x.LineCount(); got != len(widths) {
+		t.Fatalf("LineCount() of %q = %d, want %d", s, got, len(widths))
+	}
+	for line, width := range widths {
+		if got := x.LineWidth(line); got != width {
+			t.Errorf("LineWidth(%d) of %q = %d, want %d", line, s, got, width)
+		}
+	}
+
+	for offset, want := range positions {
+		if line, col := x.Position(offset); line != want.line || col != want.col {
+			t.Errorf("Position(%d) of %q = %d, %d, want %d, %d", offset, s, line, col, want.line, want.col)
+		}
+	}
+
+	// Offset finds the first cluster of the line ending after col.
+	for line, start := range starts {
+		end := start
+		for end < len(s) && positions[end].line == line && lineBreakAt(s[end:]) == 0 {
+			end++
+		}
+		want := start
+		for col := 0; col <= widths[line]+1; col++ {
+			for want < end && positions[want].col+positions[want].width <= col {
+				want = positions[want].end
+			}
+			if got := x.Offset(line, col); got != want {
+				t.Errorf("Offset(%d, %d) of %q = %d, want %d", line, col, s, got, want)
+			}
+		}
+	}
+}
+
+// checkLineBlocks checks that the blocks of x start at line starts, and that
+// the tree over them sums them up.
+func checkLineBlocks(t *testing.T, x *LineIndex) {
+	t.Helper()
+
+	offset := 0
+	var sum lineSum
+	for i, b := range x.blocks {
+		if i+1 < len(x.blocks) {
+			n := len(b.s)
+			if n == 0 || !strings.ContainsRune(lineBreaks, rune(b.s[n-1])) && !strings.HasSuffix(b.s, "\u2028") && !strings.HasSuffix(b.s, "\u2029") {
+				t.Fatalf("block %d at %d does not end with a line break", i, offset)
+			}
+			if strings.HasSuffix(b.s, "\r") && strings.HasPrefix(x.blocks[i+1].s, "\n") {
+				t.Fatalf("blocks %d and %d split CR LF at %d", i, i+1, offset+n)
+			}
+		}
+		if b.max != slices.Max(b.widths) {
+			t.Fatalf("block %d at %d: max %d, want %d", i, offset, b.max, slices.Max(b.widths))
+		}
+		offset += len(b.s)
+		sum = sum.add(b.sum())
+	}
+	if x.sums[1] != sum {
+		t.Fatalf("tree root = %+v, want %+v", x.sums[1], sum)
+	}
+	for i := len(x.sums)/2 - 1; i > 0; i-- {
+		if x.sums[i] != x.sums[2*i].add(x.sums[2*i+1]) {
+			t.Fatalf("tree node %d = %+v, want the sum of its children", i, x.sums[i])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package uniwidth
+
+import (
+	"iter"
+	"strings"
+)
+
+// lineBreaks are the characters that end a line: LF, CR (alone or before
+// LF), LINE SEPARATOR and PARAGRAPH SEPARATOR.
+const lineBreaks = "\n\r\u2028\u2029"
+
+// Lines returns an iterator over the lines of s and their display widths.
+//
+// Lines end at LF, CR LF, a lone CR, U+2028 LINE SEPARATOR or U+2029
+// PARAGRAPH SEPARATOR; the line break is not part of the line. As with
+// bufio.ScanLines, a line break at the end of s does not start another line,
+// so "a\n" is one line and "" none, while "\n" is one empty line.
+//
+// Example:
+//
+//	for line, width := range uniwidth.Lines("Usage:\r\n  uniwidth 世界\n") {
+//	    fmt.Printf("%q %d\n", line, width)
+//	}
+//	// "Usage:" 6
+//	// "  uniwidth 世界" 15
+//
+// Without options, each line is measured with StringWidth; with options,
+// with StringWidthWithOptions.
+func Lines(s string, opts ...Option) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		for rest := s; len(rest) > 0; {
+			line := rest
+			rest = ""
+			if i := strings.IndexAny(line, lineBreaks); i >= 0 {
+				line, rest = line[:i], line[i+lineBreakLen(line[i:]):]
+			}
+
+			width := 0
+			if len(opts) == 0 {
+				width = StringWidth(line)
+			} else {
+				width = StringWidthWithOptions(line, opts...)
+			}
+			if !yield(line, width) {
+				return
+			}
+		}
+	}
+}
+
+// Dimensions returns the width of the widest line of s and the number of
+// lines, the size of the block s occupies on screen. Lines are split as in
+// Lines, so Dimensions("") is 0, 0 and a trailing line break adds no line.
+//
+// Example:
+//
+//	width, height := uniwidth.Dimensions("Usage:\n  uniwidth 世界\n")
+//	// width = 15, height = 2
+//
+// Without options, each line is measured with StringWidth; with options,
+// with StringWidthWithOptions.
+func Dimensions(s string, opts ...Option) (width, height int) {
+	for _, w := range Lines(s, opts...) {
+		width = max(width, w)
+		height++
+	}
+	return width, height
+}
+
+// MaxLineWidth returns the width of the widest line of s, where StringWidth
+// would return the sum of all lines. It is the width half of Dimensions.
+//
+// Example:
+//
+//	width := uniwidth.MaxLineWidth("Usage:\n  uniwidth 世界\n")
+//	// width = 15
+func MaxLineWidth(s string, opts ...Option) int {
+	width, _ := Dimensions(s, opts...)
+	return width
+}
+
+// lineBreakLen returns the length of the line break at the start of s.
+func lineBreakLen(s string) int {
+	switch {
+	case strings.HasPrefix(s, "\r\n"):
+		return 2
+	case s[0] == '\n', s[0] == '\r':
+		return 1
+	}
+	return len("\u2028") // and U+2029
+}
@@ -0,0 +1,97 @@
+package uniwidth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	type line struct {
+		s     string
+		width int
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		opts []Option
+		want []line
+	}{
+		{"empty", "", nil, nil},
+		{"one line", "hello", nil, []line{{"hello", 5}}},
+		{"trailing LF", "hello\n", nil, []line{{"hello", 5}}},
+		{"only LF", "\n", nil, []line{{"", 0}}},
+		{"LF", "ab\n世界", nil, []line{{"ab", 2}, {"世界", 4}}},
+		{"CR LF", "ab\r\n世界\r\n", nil, []line{{"ab", 2}, {"世界", 4}}},
+		{"lone CR", "ab\r世界", nil, []line{{"ab", 2}, {"世界", 4}}},
+		{"LF CR", "ab\n\rc", nil, []line{{"ab", 2}, {"", 0}, {"c", 1}}},
+		{"empty lines", "a\n\n\nb", nil, []line{{"a", 1}, {"", 0}, {"", 0}, {"b", 1}}},
+		{"line separator", "a\u2028bc", nil, []line{{"a", 1}, {"bc", 2}}},
+		{"paragraph separator", "a\u2029bc\u2029", nil, []line{{"a", 1}, {"bc", 2}}},
+		{"mixed", "a\nb\r\nc\rd\u2028e\u2029f", nil, []line{{"a", 1}, {"b", 1}, {"c", 1}, {"d", 1}, {"e", 1}, {"f", 1}}},
+		{"emoji", "👨‍👩‍👧\n👍🏽!", nil, []line{{"👨‍👩‍👧", 2}, {"👍🏽!", 3}}},
+		{"other controls", "a\tb\x1b\n", nil, []line{{"a\tb\x1b", 2}}},
+		{"NEL is not a line break", "a\u0085b", nil, []line{{"a\u0085b", 2}}},
+		{"options", "±\n±±", []Option{WithEastAsianAmbiguous(EAWide)}, []line{{"±", 2}, {"±±", 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []line
+			for s, width := range Lines(tt.s, tt.opts...) {
+				got = append(got, line{s, width})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Lines(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines_EarlyBreak(t *testing.T) {
+	seq := Lines("a\nb\nc\nd")
+
+	// The iterator can be reused and stops when the loop breaks.
+	for range 2 {
+		var got []string
+		for line := range seq {
+			got = append(got, line)
+			if len(got) == 2 {
+				break
+			}
+		}
+		if want := []string{"a", "b"}; !slices.Equal(got, want) {
+			t.Errorf("lines before break = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestDimensions(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		opts   []Option
+		width  int
+		height int
+	}{
+		{"empty", "", nil, 0, 0},
+		{"one line", "hello", nil, 5, 1},
+		{"only LF", "\n", nil, 0, 1},
+		{"help text", "Usage:\n  uniwidth 世界\n\nFlags:\r\n  -json\n", nil, 15, 5},
+		{"widest last", "a\nbb\nccc", nil, 3, 3},
+		{"separators", "世界\u2028a\u2029", nil, 4, 2},
+		{"options", "±±\na", []Option{WithEastAsianAmbiguous(EAWide)}, 4, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := Dimensions(tt.s, tt.opts...)
+			if width != tt.width || height != tt.height {
+				t.Errorf("Dimensions(%q) = %d, %d, want %d, %d", tt.s, width, height, tt.width, tt.height)
+			}
+			if width := MaxLineWidth(tt.s, tt.opts...); width != tt.width {
+				t.Errorf("MaxLineWidth(%q) = %d, want %d", tt.s, width, tt.width)
+			}
+		})
+	}
+}
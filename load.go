@@ -0,0 +1,14 @@
+//go:build !purego
+
+package uniwidth
+
+import "unsafe"
+
+// load64 returns the 8 bytes of s starting at i as one uint64 in native byte
+// order. The caller guarantees i+8 <= len(s). The SWAR code only tests
+// whether any byte matches, so the byte order does not change its results.
+//
+//nolint:gosec // G103: unsafe usage is intentional for SWAR performance optimization
+func load64(s string, i int) uint64 {
+	return *(*uint64)(unsafe.Add(unsafe.Pointer(unsafe.StringData(s)), i))
+}
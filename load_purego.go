@@ -0,0 +1,13 @@
+//go:build purego
+
+package uniwidth
+
+// load64 returns the 8 bytes of s starting at i as a little-endian uint64,
+// the way binary.LittleEndian.Uint64 reads a []byte, but without converting
+// s. The compiler merges the byte loads into one load where the platform
+// allows it.
+func load64(s string, i int) uint64 {
+	b := s[i : i+8]
+	return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
+		uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+}
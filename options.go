@@ -31,6 +31,17 @@ type Options struct {
 	// or text (width 1). When true, emoji are treated as width 2.
 	// Default: true (emoji presentation)
 	EmojiPresentation bool
+
+	// LoneRegionalIndicator specifies the width of a regional indicator that
+	// is not part of a flag pair (a lone 🇺, or the last of an odd-length run).
+	// Terminals differ here: some render it as 1 column, others as 2.
+	// Default: 2
+	LoneRegionalIndicator int
+
+	// Controls specifies how control characters (C0, DEL and C1) are
+	// rendered, and so how wide they are.
+	// Default: ControlZero (width 0)
+	Controls ControlPolicy
 }
 
 // Option is a functional option for configuring Unicode width calculation.
@@ -39,8 +50,10 @@ type Option func(*Options)
 // defaultOptions returns the default configuration.
 func defaultOptions() Options {
 	return Options{
-		EastAsianAmbiguous: EANarrow, // Width 1 for neutral context
-		EmojiPresentation:  true,     // Emoji are wide by default
+		EastAsianAmbiguous:    EANarrow, // Width 1 for neutral context
+		EmojiPresentation:     true,     // Emoji are wide by default
+		LoneRegionalIndicator: 2,        // Unpaired regional indicators are wide
+		Controls:              ControlZero,
 	}
 }
 
@@ -81,6 +94,38 @@ func WithEmojiPresentation(emoji bool) Option {
 	}
 }
 
+// WithLoneRegionalIndicatorWidth sets the width of a regional indicator that is
+// not part of a flag pair. Regional indicators always pair from the left, so in
+// an odd-length run only the last one is unpaired. Widths below 1 are treated
+// as 1 and widths above 2 as 2.
+//
+// Example:
+//
+//	// Terminal renders a lone regional indicator as a single column
+//	width := uniwidth.StringWidthWithOptions("🇺🇸🇫", uniwidth.WithLoneRegionalIndicatorWidth(1))
+//	// width = 3 (🇺🇸 flag = 2, lone 🇫 = 1)
+func WithLoneRegionalIndicatorWidth(width int) Option {
+	return func(o *Options) {
+		o.LoneRegionalIndicator = min(max(width, 1), 2)
+	}
+}
+
+// WithControlPolicy sets how control characters are rendered: not at all
+// (ControlZero, the default), in caret notation (ControlCaret), as
+// <U+XXXX> (ControlHex) or as U+FFFD (ControlReplacement). Escape produces
+// the matching text.
+//
+// Example:
+//
+//	// A pager that shows controls as ^A
+//	width := uniwidth.StringWidthWithOptions("a\x01b", uniwidth.WithControlPolicy(uniwidth.ControlCaret))
+//	// width = 4 (a=1, ^A=2, b=1)
+func WithControlPolicy(policy ControlPolicy) Option {
+	return func(o *Options) {
+		o.Controls = policy
+	}
+}
+
 // RuneWidthWithOptions returns the visual width of a rune with custom options.
 //
 // This function applies the same tiered lookup strategy as RuneWidth, but allows
@@ -102,22 +147,16 @@ func RuneWidthWithOptions(r rune, opts ...Option) int {
 		opt(&options)
 	}
 
-	// Use the same tiered lookup as RuneWidth
-	width := runeWidthInternal(r)
-
-	// Special handling for ambiguous characters
-	if width == -1 {
-		// This is an ambiguous character - use configured width
-		return int(options.EastAsianAmbiguous)
-	}
-
-	return width
+	// Use the same tiered lookup as RuneWidth, with the configured width
+	// for ambiguous characters
+	return options.runeWidth(r)
 }
 
 // StringWidthWithOptions calculates the visual width of a string with custom options.
 //
-// This function applies the same fast paths as StringWidth, but allows
-// customization of ambiguous character handling and emoji presentation.
+// This function applies the same fast paths and emoji sequence handling as
+// StringWidth, but allows customization of ambiguous character handling and
+// emoji presentation.
 //
 // Example:
 //
@@ -141,24 +180,42 @@ func StringWidthWithOptions(s string, opts ...Option) int {
 		opt(&options)
 	}
 
-	// Fast path: ASCII-only strings (no ambiguous characters in ASCII)
+	// Fast path: ASCII-only strings (no ambiguous characters in ASCII).
+	// Every byte that asciiWidth does not count is a control, and all
+	// ASCII controls are equally wide under each policy.
 	if isASCIIOnly(s) {
-		return len(s)
+		width := asciiWidth(s)
+		return width + (len(s)-width)*options.Controls.width(0)
 	}
 
-	// Iterate through runes and sum their widths
-	width := 0
-	for _, r := range s {
-		w := runeWidthInternal(r)
-		if w == -1 {
-			// Ambiguous character - use configured width
-			width += int(options.EastAsianAmbiguous)
-		} else {
-			width += w
-		}
+	// Same sequence handling as StringWidth (ZWJ, modifiers, flags, keycaps)
+	return stringWidth(s, &options)
+}
+
+// runeWidth returns the width of a rune under these options, resolving
+// ambiguous characters to the configured East Asian width. A regional
+// indicator on its own is unpaired and takes the lone regional indicator width,
+// and a control character the width of its rendering under the control policy.
+// A nil *Options measures like RuneWidth.
+func (o *Options) runeWidth(r rune) int {
+	if o == nil {
+		return RuneWidth(r)
+	}
+
+	if isControl(r) {
+		return o.Controls.width(r)
 	}
 
-	return width
+	if isRegionalIndicator(r) {
+		return o.LoneRegionalIndicator
+	}
+
+	w := runeWidthInternal(r)
+	if w == -1 {
+		// Ambiguous character - use configured width
+		return int(o.EastAsianAmbiguous)
+	}
+	return w
 }
 
 // runeWidthInternal returns the width of a rune, or -1 for ambiguous characters.
@@ -273,6 +273,72 @@ func BenchmarkStringWidthWithOptions(b *testing.B) {
 	})
 }
 
+// TestStringWidthWithOptions_EmojiSequences verifies that the options API
+// applies the same emoji sequence handling as StringWidth.
+func TestStringWidthWithOptions_EmojiSequences(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		narrow int
+		wide   int
+	}{
+		{"ZWJ family", "👨\u200D👩\u200D👧", 2, 2},
+		{"skin tone", "👍🏽", 2, 2},
+		{"flag", "🇺🇸", 2, 2},
+		{"keycap", "#\uFE0F\u20E3", 2, 2},
+		{"unqualified keycap", "7\u20E3", 2, 2},
+		{"VS16", "\u2764\uFE0F", 2, 2},
+		{"VS15", "\u2764\uFE0E", 1, 1},
+		{"ambiguous + VS16", "\u00B1\uFE0F", 1, 2}, // ± is not an emoji variation base
+		{"ambiguous + VS15", "\u25A1\uFE0E", 1, 2}, // □ is not either
+		{"CJK + VS15", "\u4E16\uFE0E", 2, 2},
+		{"ASCII + VS16", "a\uFE0F", 1, 1},
+		{"ambiguous + keycap", "±1\uFE0F\u20E3", 3, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidthWithOptions(tt.s, WithEastAsianAmbiguous(EANarrow)); got != tt.narrow {
+				t.Errorf("StringWidthWithOptions(%q, EANarrow) = %d, want %d", tt.s, got, tt.narrow)
+			}
+			if got := StringWidthWithOptions(tt.s, WithEastAsianAmbiguous(EAWide)); got != tt.wide {
+				t.Errorf("StringWidthWithOptions(%q, EAWide) = %d, want %d", tt.s, got, tt.wide)
+			}
+		})
+	}
+}
+
+// TestOptions_LoneRegionalIndicator tests the lone regional indicator width option.
+func TestOptions_LoneRegionalIndicator(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"default", nil, 2},
+		{"narrow", []Option{WithLoneRegionalIndicatorWidth(1)}, 1},
+		{"wide", []Option{WithLoneRegionalIndicatorWidth(2)}, 2},
+		{"zero clamped", []Option{WithLoneRegionalIndicatorWidth(0)}, 1},
+		{"negative clamped", []Option{WithLoneRegionalIndicatorWidth(-3)}, 1},
+		{"too wide clamped", []Option{WithLoneRegionalIndicatorWidth(5)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RuneWidthWithOptions(0x1F1FA, tt.opts...); got != tt.want {
+				t.Errorf("RuneWidthWithOptions(U+1F1FA) = %d, want %d", got, tt.want)
+			}
+			if got := StringWidthWithOptions("🇺", tt.opts...); got != tt.want {
+				t.Errorf("StringWidthWithOptions(lone RI) = %d, want %d", got, tt.want)
+			}
+			// A flag pair is always width 2, regardless of the option.
+			if got := StringWidthWithOptions("🇺🇸", tt.opts...); got != 2 {
+				t.Errorf("StringWidthWithOptions(flag) = %d, want 2", got)
+			}
+		})
+	}
+}
+
 // TestRuneWidthWithOptions_EmojiExtendedRanges tests additional emoji ranges
 func TestRuneWidthWithOptions_EmojiExtendedRanges(t *testing.T) {
 	tests := []struct {
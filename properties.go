@@ -0,0 +1,94 @@
+package uniwidth
+
+import "unicode/utf8"
+
+// Property bits of the generated emoji property table (emoji-data.txt and
+// emoji-variation-sequences.txt). Must match the encoding in
+// cmd/generate-tables.
+const (
+	propEmoji                = 1 << 0 // Emoji
+	propEmojiPresentation    = 1 << 1 // Emoji_Presentation
+	propExtendedPictographic = 1 << 2 // Extended_Pictographic
+	propEmojiModifier        = 1 << 3 // Emoji_Modifier
+	propEmojiModifierBase    = 1 << 4 // Emoji_Modifier_Base
+	propEmojiComponent       = 1 << 5 // Emoji_Component
+	propEmojiVariationBase   = 1 << 6 // base of an emoji variation sequence
+)
+
+// emojiProperties returns the emoji property bits of r, or 0 for invalid runes.
+//
+// Performance: O(1), 0 allocations (3-stage table lookup).
+func emojiProperties(r rune) uint8 {
+	if r < 0 || r > utf8.MaxRune {
+		return 0
+	}
+	cp := uint32(r) //nolint:gosec // G115: r is within 0–0x10FFFF here
+	midIdx := emojiPropsMiddle[emojiPropsRoot[cp>>13]][cp>>7&0x3F]
+	return emojiPropsLeaves[midIdx][cp&0x7F]
+}
+
+// IsAmbiguous reports whether r has East Asian Width "Ambiguous": its width
+// is 1 or 2 depending on WithEastAsianAmbiguous. RuneWidth measures these
+// characters as narrow.
+//
+// Characters resolved by the CJK and emoji fast paths are never ambiguous,
+// matching RuneWidthWithOptions.
+func IsAmbiguous(r rune) bool {
+	return runeWidthInternal(r) == -1
+}
+
+// IsWide reports whether RuneWidth(r) is 2.
+func IsWide(r rune) bool {
+	return RuneWidth(r) == 2
+}
+
+// IsZeroWidth reports whether RuneWidth(r) is 0: controls, combining marks,
+// format characters such as ZWJ, variation selectors and the like.
+func IsZeroWidth(r rune) bool {
+	return RuneWidth(r) == 0
+}
+
+// IsEmoji reports whether r has the Unicode Emoji property. Note that this
+// includes characters that are usually rendered as text, such as the ASCII
+// digits, '#', '*', © and ®.
+func IsEmoji(r rune) bool {
+	return emojiProperties(r)&propEmoji != 0
+}
+
+// IsEmojiPresentation reports whether r has the Emoji_Presentation property:
+// it is rendered as an emoji (width 2) by default, without U+FE0F.
+func IsEmojiPresentation(r rune) bool {
+	return emojiProperties(r)&propEmojiPresentation != 0
+}
+
+// IsExtendedPictographic reports whether r has the Extended_Pictographic
+// property, which decides where ZWJ emoji sequences may join (UAX #29 GB11).
+func IsExtendedPictographic(r rune) bool {
+	return isExtendedPictographic(r)
+}
+
+// IsEmojiModifier reports whether r is an emoji modifier: one of the five
+// Fitzpatrick skin tones U+1F3FB-U+1F3FF.
+func IsEmojiModifier(r rune) bool {
+	return isEmojiModifier(r)
+}
+
+// IsEmojiModifierBase reports whether r has the Emoji_Modifier_Base property:
+// an emoji modifier after it selects its skin tone (👍 + 🏽 = 👍🏽).
+func IsEmojiModifierBase(r rune) bool {
+	return isEmojiModifierBase(r)
+}
+
+// IsEmojiComponent reports whether r has the Emoji_Component property: it
+// can appear inside emoji sequences (keycap bases, ZWJ, U+FE0F, U+20E3,
+// regional indicators, skin tones, hair styles and tags) but does not
+// usually stand alone as an emoji.
+func IsEmojiComponent(r rune) bool {
+	return emojiProperties(r)&propEmojiComponent != 0
+}
+
+// IsRegionalIndicator reports whether r is a regional indicator symbol
+// (U+1F1E6-U+1F1FF). A pair of them forms a flag.
+func IsRegionalIndicator(r rune) bool {
+	return isRegionalIndicator(r)
+}
@@ -0,0 +1,238 @@
+package uniwidth
+
+import (
+	"bufio"
+	"cmp"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// loadUCDRanges reads a trimmed UCD file from testdata, with lines of
+// FIRST..LAST ; VALUE or FIRST ; VALUE, and returns the sorted ranges of each
+// value.
+// A line listing a sequence (FIRST SECOND ; VALUE) gives the range of its
+// first codepoint. It parses the file on its own, so the tests do not share
+// a bug with the generator.
+func loadUCDRanges(t *testing.T, name string) map[string][]runeRange {
+	t.Helper()
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("open UCD file: %v", err)
+	}
+	defer f.Close()
+
+	ranges := make(map[string][]runeRange)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cps, value, ok := strings.Cut(line, ";")
+		fields := strings.Fields(cps)
+		if !ok || len(fields) == 0 {
+			t.Fatalf("malformed line %q in %s", line, name)
+		}
+		first, last, isRange := strings.Cut(fields[0], "..")
+		if !isRange {
+			last = first
+		}
+		lo, err1 := strconv.ParseUint(first, 16, 32)
+		hi, err2 := strconv.ParseUint(last, 16, 32)
+		if err1 != nil || err2 != nil || lo > hi || hi > 0x10FFFF {
+			t.Fatalf("malformed line %q in %s", line, name)
+		}
+
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), ";"))
+		ranges[value] = append(ranges[value], runeRange{rune(lo), rune(hi)})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read UCD file: %v", err)
+	}
+
+	for _, r := range ranges {
+		slices.SortFunc(r, func(a, b runeRange) int { return cmp.Compare(a.first, b.first) })
+	}
+	return ranges
+}
+
+// TestPredicates_ConsistentWithRuneWidth verifies the width predicates
+// against RuneWidth and RuneWidthWithOptions for every codepoint.
+func TestPredicates_ConsistentWithRuneWidth(t *testing.T) {
+	mismatches := 0
+	const maxMismatchLog = 20
+
+	for cp := rune(0); cp <= 0x10FFFF; cp++ {
+		w := RuneWidth(cp)
+		narrow := RuneWidthWithOptions(cp, WithEastAsianAmbiguous(EANarrow))
+		wide := RuneWidthWithOptions(cp, WithEastAsianAmbiguous(EAWide))
+
+		ok := IsWide(cp) == (w == 2) &&
+			IsZeroWidth(cp) == (w == 0) &&
+			IsAmbiguous(cp) == (narrow != wide)
+		if !ok {
+			mismatches++
+			if mismatches <= maxMismatchLog {
+				t.Errorf("%U: IsWide=%v IsZeroWidth=%v IsAmbiguous=%v, RuneWidth=%d, narrow=%d, wide=%d",
+					cp, IsWide(cp), IsZeroWidth(cp), IsAmbiguous(cp), w, narrow, wide)
+			}
+		}
+	}
+
+	if mismatches > maxMismatchLog {
+		t.Errorf("... and %d more mismatches (total: %d)", mismatches-maxMismatchLog, mismatches)
+	}
+}
+
+// TestEmojiProperties_Exhaustive verifies every bit of the generated emoji
+// property table against the trimmed copies of emoji-data.txt and
+// emoji-variation-sequences.txt in testdata, for every codepoint. The check
+// is only independent of the generator if the copies were trimmed from the
+// published files (see their Source lines).
+func TestEmojiProperties_Exhaustive(t *testing.T) {
+	data := loadUCDRanges(t, "testdata/emoji-data.txt")
+	sequences := loadUCDRanges(t, "testdata/emoji-variation-sequences.txt")
+
+	properties := []struct {
+		name   string
+		bit    uint8
+		ranges []runeRange
+	}{
+		{"Emoji", propEmoji, data["Emoji"]},
+		{"Emoji_Presentation", propEmojiPresentation, data["Emoji_Presentation"]},
+		{"Extended_Pictographic", propExtendedPictographic, data["Extended_Pictographic"]},
+		{"Emoji_Modifier", propEmojiModifier, data["Emoji_Modifier"]},
+		{"Emoji_Modifier_Base", propEmojiModifierBase, data["Emoji_Modifier_Base"]},
+		{"Emoji_Component", propEmojiComponent, data["Emoji_Component"]},
+		{"variation base", propEmojiVariationBase, sequences["emoji style"]},
+	}
+
+	for _, p := range properties {
+		t.Run(p.name, func(t *testing.T) {
+			mismatches, count := 0, 0
+			const maxMismatchLog = 20
+
+			for cp := rune(0); cp <= 0x10FFFF; cp++ {
+				want := binarySearch(cp, p.ranges)
+				if want {
+					count++
+				}
+				if got := emojiProperties(cp)&p.bit != 0; got != want {
+					mismatches++
+					if mismatches <= maxMismatchLog {
+						t.Errorf("%U: %s = %v in table, %v in testdata", cp, p.name, got, want)
+					}
+				}
+			}
+
+			if mismatches > maxMismatchLog {
+				t.Errorf("... and %d more mismatches (total: %d)", mismatches-maxMismatchLog, mismatches)
+			}
+			if count == 0 {
+				t.Errorf("no %s codepoints in the reference ranges", p.name)
+			}
+		})
+	}
+}
+
+// TestEmojiPredicates_Invariants checks relations between the emoji
+// properties that hold for every codepoint in emoji-data.txt.
+func TestEmojiPredicates_Invariants(t *testing.T) {
+	for cp := rune(0); cp <= 0x10FFFF; cp++ {
+		if IsEmojiPresentation(cp) && !IsEmoji(cp) {
+			t.Errorf("%U: Emoji_Presentation without Emoji", cp)
+		}
+		if IsEmojiPresentation(cp) && !IsEmojiModifier(cp) && RuneWidth(cp) != 2 {
+			t.Errorf("%U: Emoji_Presentation but RuneWidth = %d", cp, RuneWidth(cp))
+		}
+		if IsRegionalIndicator(cp) && !IsEmojiPresentation(cp) {
+			t.Errorf("%U: regional indicator without Emoji_Presentation", cp)
+		}
+		if IsEmojiModifierBase(cp) && !IsExtendedPictographic(cp) {
+			t.Errorf("%U: Emoji_Modifier_Base without Extended_Pictographic", cp)
+		}
+		if IsEmojiModifier(cp) && !IsEmojiComponent(cp) {
+			t.Errorf("%U: Emoji_Modifier without Emoji_Component", cp)
+		}
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	tests := []struct {
+		name string
+		r    rune
+		fn   func(rune) bool
+		want bool
+	}{
+		{"IsAmbiguous ±", '±', IsAmbiguous, true},
+		{"IsAmbiguous box drawing", '─', IsAmbiguous, true},
+		{"IsAmbiguous a", 'a', IsAmbiguous, false},
+		{"IsAmbiguous CJK", '世', IsAmbiguous, false},
+
+		{"IsWide CJK", '世', IsWide, true},
+		{"IsWide emoji", '😀', IsWide, true},
+		{"IsWide fullwidth", 'Ａ', IsWide, true},
+		{"IsWide ambiguous", '±', IsWide, false},
+		{"IsWide a", 'a', IsWide, false},
+
+		{"IsZeroWidth ZWJ", 0x200D, IsZeroWidth, true},
+		{"IsZeroWidth combining", 0x0301, IsZeroWidth, true},
+		{"IsZeroWidth control", '\n', IsZeroWidth, true},
+		{"IsZeroWidth a", 'a', IsZeroWidth, false},
+
+		{"IsEmoji grinning face", '😀', IsEmoji, true},
+		{"IsEmoji heart", '❤', IsEmoji, true},
+		{"IsEmoji digit", '1', IsEmoji, true},
+		{"IsEmoji copyright", '©', IsEmoji, true},
+		{"IsEmoji letter", 'a', IsEmoji, false},
+		{"IsEmoji CJK", '世', IsEmoji, false},
+		{"IsEmoji negative", -1, IsEmoji, false},
+		{"IsEmoji beyond max rune", 0x110000, IsEmoji, false},
+
+		{"IsEmojiPresentation grinning face", '😀', IsEmojiPresentation, true},
+		{"IsEmojiPresentation watch", '⌚', IsEmojiPresentation, true},
+		{"IsEmojiPresentation heart", '❤', IsEmojiPresentation, false},
+		{"IsEmojiPresentation digit", '1', IsEmojiPresentation, false},
+		{"IsEmojiPresentation skin tone", 0x1F3FD, IsEmojiPresentation, true},
+
+		{"IsExtendedPictographic heart", '❤', IsExtendedPictographic, true},
+		{"IsExtendedPictographic man", '👨', IsExtendedPictographic, true},
+		{"IsExtendedPictographic a", 'a', IsExtendedPictographic, false},
+
+		{"IsEmojiModifier medium", 0x1F3FD, IsEmojiModifier, true},
+		{"IsEmojiModifier face", '😀', IsEmojiModifier, false},
+
+		{"IsEmojiModifierBase thumbs up", '👍', IsEmojiModifierBase, true},
+		{"IsEmojiModifierBase woman", '👩', IsEmojiModifierBase, true},
+		{"IsEmojiModifierBase heart", '❤', IsEmojiModifierBase, false},
+		{"IsEmojiModifierBase grinning face", '😀', IsEmojiModifierBase, false},
+
+		{"IsEmojiComponent ZWJ", 0x200D, IsEmojiComponent, true},
+		{"IsEmojiComponent VS16", 0xFE0F, IsEmojiComponent, true},
+		{"IsEmojiComponent keycap", 0x20E3, IsEmojiComponent, true},
+		{"IsEmojiComponent digit", '7', IsEmojiComponent, true},
+		{"IsEmojiComponent skin tone", 0x1F3FD, IsEmojiComponent, true},
+		{"IsEmojiComponent red hair", 0x1F9B0, IsEmojiComponent, true},
+		{"IsEmojiComponent tag", 0xE0067, IsEmojiComponent, true},
+		{"IsEmojiComponent face", '😀', IsEmojiComponent, false},
+
+		{"IsRegionalIndicator U", 0x1F1FA, IsRegionalIndicator, true},
+		{"IsRegionalIndicator A", 0x1F1E6, IsRegionalIndicator, true},
+		{"IsRegionalIndicator Z", 0x1F1FF, IsRegionalIndicator, true},
+		{"IsRegionalIndicator before", 0x1F1E5, IsRegionalIndicator, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.r); got != tt.want {
+				t.Errorf("%s(%U) = %v, want %v", tt.name, tt.r, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package uniwidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxCursorMove caps the count of a CSI cursor movement, as terminals stop
+// the cursor at the right margin.
+const maxCursorMove = 1 << 16
+
+// RenderedWidth returns the width of the line left on screen after a
+// terminal renders s, for output that overwrites itself: progress bars and
+// spinners that return to the start of the line with CR, step back with
+// BS, or move the cursor with escape sequences.
+//
+// The cursor starts at column 0 of an unbounded line:
+//   - text is written at the cursor and moves it right, overwriting what
+//     was there (overwriting half of a wide character blanks the other
+//     half, which stays occupied)
+//   - CR moves to column 0, BS one column left, and HT to the next
+//     multiple of 8
+//   - CSI n C and CSI n D move n columns right and left, CSI n G to column
+//     n, and CSI K, 1 K and 2 K erase to the end of the line, to the start
+//     of the line, or the whole line
+//   - LF starts a new line, and the result is the width of the widest line
+//   - other controls and escape sequences (colors, hyperlinks, titles) are
+//     ignored
+//
+// The width is the column after the rightmost occupied cell; moving the
+// cursor alone occupies nothing.
+//
+// Example:
+//
+//	uniwidth.RenderedWidth("downloading... 10%\rdone")     // 18 ("doneloading... 10%")
+//	uniwidth.RenderedWidth("downloading... 10%\r\x1b[Kdone") // 4
+//	uniwidth.RenderedWidth("世界\bx")                        // 4 ("世 x", 界 split)
+//
+// Without options, text is measured as StringWidth measures it; with
+// options, as StringWidthWithOptions does. Renderer measures output written
+// in pieces.
+func RenderedWidth(s string, opts ...Option) int {
+	// Fast path: printable ASCII never moves the cursor back.
+	if isASCIIOnly(s) && asciiWidth(s) == len(s) {
+		return len(s)
+	}
+
+	r := Renderer{o: clusterOptions(opts)}
+	r.render(s, true)
+	return r.Width()
+}
+
+// Renderer is the streaming form of RenderedWidth: it simulates the cursor
+// over output written to it in pieces, such as the successive frames of a
+// progress bar. The zero Renderer measures text like StringWidth.
+//
+// Runes, clusters and escape sequences may be split across writes. The last
+// cluster written is counted at once, and updated if the next write extends
+// it; an incomplete rune or escape sequence at the end is held back until the
+// rest of it is written.
+type Renderer struct {
+	o       *Options
+	cells   []span // occupied columns of the current line, in order
+	col     int    // cursor column
+	width   int    // width of the widest finished line
+	pending string // held-back end of the output, see render
+}
+
+// span is a run of occupied columns [start, end).
+type span struct {
+	start, end int
+}
+
+// NewRenderer returns a Renderer that measures text with opts.
+//
+// Example:
+//
+//	r := uniwidth.NewRenderer(uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide))
+//	fmt.Fprint(r, "±±\r")
+//	fmt.Fprint(r, "±")
+//	r.Width() // 4
+func NewRenderer(opts ...Option) *Renderer {
+	return &Renderer{o: clusterOptions(opts)}
+}
+
+// Write renders p. It always returns len(p), nil.
+func (r *Renderer) Write(p []byte) (int, error) {
+	s := r.pending + string(p)
+	r.pending = ""
+	r.pending = r.render(s, false)
+	return len(p), nil
+}
+
+// WriteString renders s. It always returns len(s), nil.
+func (r *Renderer) WriteString(s string) (int, error) {
+	n := len(s)
+	s = r.pending + s
+	r.pending = ""
+	r.pending = r.render(s, false)
+	return n, nil
+}
+
+// Width returns the width of the widest line rendered so far.
+func (r *Renderer) Width() int {
+	width := max(r.width, r.lineWidth())
+	if w := r.pendingWidth(); w > 0 {
+		width = max(width, r.col+w)
+	}
+	return width
+}
+
+// Column returns the column of the cursor.
+func (r *Renderer) Column() int {
+	return r.col + r.pendingWidth()
+}
+
+// Reset clears the screen state, keeping the options.
+func (r *Renderer) Reset() {
+	*r = Renderer{o: r.o, cells: r.cells[:0]}
+}
+
+// pendingWidth returns the width of the cluster held back at the end of
+// the output, or 0 if the output ends with an incomplete rune or escape
+// sequence.
+func (r *Renderer) pendingWidth() int {
+	end := completeRunes(r.pending)
+	if end == 0 || isEscapeIntroducer(firstRune(r.pending)) {
+		return 0
+	}
+	_, w := nextCluster(r.pending[:end], r.o)
+	return w
+}
+
+// render renders s. Unless final is set, it stops at the last cluster, an
+// incomplete rune or an incomplete escape sequence, which later output can
+// still change, and returns the rest of s from there.
+func (r *Renderer) render(s string, final bool) (rest string) {
+	end := len(s)
+	if !final {
+		end = completeRunes(s)
+	}
+
+	for i := 0; i < end; {
+		c, size := decodeRune(s[i:end])
+		switch {
+		case c == '\r':
+			r.col = 0
+		case c == '\n':
+			r.newline()
+		case c == '\b':
+			r.col = max(r.col-1, 0)
+		case c == '\t':
+			r.col = (r.col/8 + 1) * 8
+		case isEscapeIntroducer(c):
+			n, complete := escapeLen(s[i:])
+			if !complete && !final {
+				return s[i:]
+			}
+			r.escape(s[i : i+n])
+			size = n
+		case isControl(c):
+			// Ignored, like BEL and NUL on a terminal.
+		default:
+			n, w := nextCluster(s[i:end], r.o)
+			if !final && i+n == end {
+				return s[i:]
+			}
+			r.write(w)
+			size = n
+		}
+		i += size
+	}
+	return s[end:]
+}
+
+// lineWidth returns the width of the current line: the end of its last
+// occupied span.
+func (r *Renderer) lineWidth() int {
+	if len(r.cells) == 0 {
+		return 0
+	}
+	return r.cells[len(r.cells)-1].end
+}
+
+// newline finishes the current line and moves to the start of the next.
+func (r *Renderer) newline() {
+	r.width = max(r.width, r.lineWidth())
+	r.cells = r.cells[:0]
+	r.col = 0
+}
+
+// write writes a cluster w columns wide at the cursor.
+func (r *Renderer) write(w int) {
+	if w <= 0 {
+		return // zero-width clusters render in the previous cell
+	}
+	r.fill(r.col, r.col+w)
+	r.col += w
+}
+
+// fill marks the columns [start, end) occupied.
+func (r *Renderer) fill(start, end int) {
+	// Fast path: text written at or before the end of the line.
+	if n := len(r.cells); n > 0 && r.cells[n-1].start <= start && start <= r.cells[n-1].end {
+		r.cells[n-1].end = max(r.cells[n-1].end, end)
+		return
+	}
+
+	// Merge the spans that touch [start, end) into it.
+	i := 0
+	for i < len(r.cells) && r.cells[i].end < start {
+		i++
+	}
+	j := i
+	for j < len(r.cells) && r.cells[j].start <= end {
+		start = min(start, r.cells[j].start)
+		end = max(end, r.cells[j].end)
+		j++
+	}
+	if i == j {
+		r.cells = append(r.cells, span{})
+		copy(r.cells[i+1:], r.cells[i:])
+	} else {
+		r.cells = append(r.cells[:i+1], r.cells[j:]...)
+	}
+	r.cells[i] = span{start, end}
+}
+
+// erase marks the columns [start, end) unoccupied.
+func (r *Renderer) erase(start, end int) {
+	var cells []span
+	for _, c := range r.cells {
+		if c.start < start {
+			cells = append(cells, span{c.start, min(c.end, start)})
+		}
+		if c.end > end {
+			cells = append(cells, span{max(c.start, end), c.end})
+		}
+	}
+	r.cells = cells
+}
+
+// escape applies the cursor movement or erase of a complete escape
+// sequence; other sequences have no effect.
+func (r *Renderer) escape(seq string) {
+	var params string
+	switch {
+	case strings.HasPrefix(seq, "\x1b["):
+		params = seq[len("\x1b["):]
+	case strings.HasPrefix(seq, "\u009b"):
+		params = seq[len("\u009b"):]
+	default:
+		return
+	}
+	if params == "" {
+		return
+	}
+	params, final := params[:len(params)-1], params[len(params)-1]
+
+	// A single optional decimal parameter, without private markers or
+	// intermediate bytes.
+	n := 0
+	for i := 0; i < len(params); i++ {
+		if params[i] < '0' || params[i] > '9' {
+			return
+		}
+		n = min(n*10+int(params[i]-'0'), maxCursorMove)
+	}
+
+	switch final {
+	case 'C': // CUF, cursor forward
+		r.col += max(n, 1)
+	case 'D': // CUB, cursor back
+		r.col = max(r.col-max(n, 1), 0)
+	case 'G': // CHA, cursor horizontal absolute (1-based)
+		r.col = max(n, 1) - 1
+	case 'K': // EL, erase in line
+		switch n {
+		case 0:
+			r.erase(r.col, maxInt)
+		case 1:
+			r.erase(0, r.col+1)
+		case 2:
+			r.erase(0, maxInt)
+		}
+	}
+}
+
+// maxInt is the largest int, the end of an unbounded line.
+const maxInt = int(^uint(0) >> 1)
+
+// escapeLen returns the length of the escape sequence at the start of s,
+// which starts with ESC or a C1 introducer, and whether it is complete: if
+// s ends before the sequence does, its length is len(s).
+func escapeLen(s string) (n int, complete bool) {
+	c, size := decodeRune(s)
+	n = skipEscapeSequence(s, size, c)
+	if n < len(s) {
+		return n, true
+	}
+
+	// s ends with the sequence: it is complete if its last byte ends it.
+	body := s[size:]
+	switch {
+	case c == 0x9B:
+		return n, endsCSI(body)
+	case c != 0x1B:
+		return n, endsControlString(body)
+	case body == "":
+		return n, false
+	case body[0] == '[':
+		return n, endsCSI(body[1:])
+	case strings.IndexByte("]PX^_", body[0]) >= 0:
+		return n, endsControlString(body[1:])
+	default: // ESC, intermediate bytes (0x20-0x2F), final byte
+		return n, body[len(body)-1] >= 0x30
+	}
+}
+
+// endsCSI reports whether the parameters of a CSI sequence, as far as
+// skipCSI took them, end with a final byte.
+func endsCSI(params string) bool {
+	return params != "" && params[len(params)-1] >= 0x40
+}
+
+// endsControlString reports whether a control string, as far as
+// skipControlString took it, ends with BEL or ST.
+func endsControlString(s string) bool {
+	return strings.HasSuffix(s, "\a") || strings.HasSuffix(s, "\x1b\\") || strings.HasSuffix(s, "\u009c")
+}
+
+// completeRunes returns the length of s without an incomplete UTF-8
+// sequence at its end, which the next write may complete.
+func completeRunes(s string) int {
+	for k := 1; k < utf8.UTFMax && k <= len(s); k++ {
+		if utf8.RuneStart(s[len(s)-k]) {
+			if !utf8.FullRuneInString(s[len(s)-k:]) {
+				return len(s) - k
+			}
+			break
+		}
+	}
+	return len(s)
+}
+
+// firstRune returns the first rune of s, or 0 if s is empty.
+func firstRune(s string) rune {
+	r, _ := decodeRune(s)
+	return r
+}
@@ -0,0 +1,148 @@
+package uniwidth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRenderedWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ASCII", "hello", 5},
+		{"CJK", "世界", 4},
+		{"CR overwrites", "downloading... 10%\rdone", 18},
+		{"CR then longer", "10%\r100% done", 9},
+		{"CR erase to end", "downloading... 10%\r\x1b[Kdone", 4},
+		{"C1 CSI erase", "downloading\r\u009bKdone", 4},
+		{"erase whole line", "abc\x1b[2K", 0},
+		{"erase whole line then write", "abcdef\x1b[2K\rxy", 2},
+		{"erase to start", "abcdef\x1b[3D\x1b[1K", 6},
+		{"erase to start at end of line", "abc\x1b[1K", 0},
+		{"erase to end inside line", "abcdef\x1b[3D\x1b[K", 3},
+		{"erase leaves earlier cells", "ab\x1b[3Ccd\x1b[1D\x1b[K", 6},
+		{"backspace", "abc\b\bx", 3},
+		{"backspace at start", "\b\bab", 2},
+		{"backspace splits wide", "世界\bx", 4},
+		{"overwrite wide start", "世界\r\x1b[1Cx", 4},
+		{"spinner", "|\b/\b-\b\\", 1},
+		{"cursor forward", "a\x1b[3Cb", 5},
+		{"cursor forward default", "a\x1b[Cb", 3},
+		{"cursor forward alone", "a\x1b[10C", 1},
+		{"cursor back", "abcd\x1b[2Dx", 4},
+		{"cursor back past start", "ab\x1b[9Dx", 2},
+		{"cursor absolute", "abcdef\x1b[3Gx", 6},
+		{"cursor absolute past end", "ab\x1b[6Gx", 6},
+		{"huge move is capped", "\x1b[99999999999999999999Cx", maxCursorMove + 1},
+		{"tab", "a\tb", 9},
+		{"trailing tab", "a\t", 1},
+		{"newline keeps widest line", "long line\nshort\r\n", 9},
+		{"newline resets cursor", "abc\n\x1b[2Cx", 3},
+		{"colors ignored", "\x1b[1;31mred\x1b[0m", 3},
+		{"private CSI ignored", "\x1b[?25lab\x1b[?25h", 2},
+		{"multiple parameters ignored", "ab\x1b[1;5D", 2},
+		{"OSC ignored", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x07", 4},
+		{"other controls ignored", "a\x07\x00b", 2},
+		{"unterminated CSI", "ab\x1b[3", 2},
+		{"unterminated OSC", "ab\x1b]0;title", 2},
+		{"emoji", "👨‍👩‍👧\r👍🏽", 2},
+		{"combining mark", "é\rx", 1},
+		{"invalid UTF-8", "a\xffb", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderedWidth(tt.s); got != tt.want {
+				t.Errorf("RenderedWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+			checkRenderer(t, tt.s, tt.want)
+		})
+	}
+}
+
+func TestRenderedWidth_Options(t *testing.T) {
+	opts := []Option{WithEastAsianAmbiguous(EAWide), WithControlPolicy(ControlCaret)}
+	if got := RenderedWidth("±±\r±\x01", opts...); got != 4 {
+		t.Errorf("RenderedWidth(\"±±\\r±\\x01\") = %d, want 4", got)
+	}
+}
+
+// checkRenderer reports an error if a Renderer given s in pieces, split at
+// every byte offset, does not measure it as wide as want.
+func checkRenderer(t *testing.T, s string, want int) {
+	t.Helper()
+
+	for i := 0; i <= len(s); i++ {
+		var r Renderer
+		r.WriteString(s[:i])
+		r.Write([]byte(s[i:]))
+		if got := r.Width(); got != want {
+			t.Errorf("Renderer width of %q + %q = %d, want %d", s[:i], s[i:], got, want)
+		}
+	}
+
+	var r Renderer
+	for i := 0; i < len(s); i++ {
+		r.WriteString(s[i : i+1])
+	}
+	if got := r.Width(); got != want {
+		t.Errorf("Renderer width of %q written byte by byte = %d, want %d", s, got, want)
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	r := NewRenderer()
+
+	fmt.Fprint(r, "downloading... 10%")
+	if w, col := r.Width(), r.Column(); w != 18 || col != 18 {
+		t.Errorf("after first frame: Width, Column = %d, %d, want 18, 18", w, col)
+	}
+
+	fmt.Fprint(r, "\r\x1b[Kdone")
+	if w, col := r.Width(), r.Column(); w != 4 || col != 4 {
+		t.Errorf("after second frame: Width, Column = %d, %d, want 4, 4", w, col)
+	}
+
+	// A flag written one regional indicator at a time is one cluster.
+	fmt.Fprint(r, " 🇯")
+	if w := r.Width(); w != 7 {
+		t.Errorf("after lone regional indicator: Width = %d, want 7", w)
+	}
+	fmt.Fprint(r, "🇵")
+	if w := r.Width(); w != 7 {
+		t.Errorf("after flag: Width = %d, want 7", w)
+	}
+
+	// An incomplete escape sequence has no effect until it ends.
+	fmt.Fprint(r, "\r\x1b[")
+	if w, col := r.Width(), r.Column(); w != 7 || col != 0 {
+		t.Errorf("after incomplete CSI: Width, Column = %d, %d, want 7, 0", w, col)
+	}
+	fmt.Fprint(r, "K")
+	if w := r.Width(); w != 0 {
+		t.Errorf("after erase: Width = %d, want 0", w)
+	}
+
+	r.Reset()
+	if w, col := r.Width(), r.Column(); w != 0 || col != 0 {
+		t.Errorf("after Reset: Width, Column = %d, %d, want 0, 0", w, col)
+	}
+}
+
+func TestRenderer_Options(t *testing.T) {
+	r := NewRenderer(WithEastAsianAmbiguous(EAWide))
+	fmt.Fprint(r, "±±\r")
+	fmt.Fprint(r, "±")
+	if w, col := r.Width(), r.Column(); w != 4 || col != 2 {
+		t.Errorf("Width, Column = %d, %d, want 4, 2", w, col)
+	}
+
+	r.Reset()
+	fmt.Fprint(r, "±")
+	if w := r.Width(); w != 2 {
+		t.Errorf("after Reset: Width = %d, want 2 (options kept)", w)
+	}
+}
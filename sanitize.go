@@ -0,0 +1,283 @@
+package uniwidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Sanitize makes untrusted text safe to print on a terminal, so that
+// StringWidth of the result is exactly the width it occupies on screen.
+// It removes or escapes:
+//   - C0 controls, DEL and C1 controls
+//   - escape sequences: CSI (ESC [ or U+009B, such as colors and cursor
+//     movement), control strings (OSC, DCS, SOS, PM, APC, such as
+//     hyperlinks and window titles) and other ESC sequences
+//   - bidi embeddings, overrides and isolates (U+202A-U+202E,
+//     U+2066-U+2069), which reorder the text around them (Trojan Source)
+//   - invalid UTF-8, including encoded surrogates
+//   - tag characters outside a well-formed emoji tag sequence (an emoji,
+//     tag specs and CANCEL TAG, as in 🏴 flags)
+//
+// With ControlZero, all of these are removed, escape sequences as a whole
+// (an unterminated sequence up to the end of s). The other policies keep
+// them visible: controls are written as Escape writes them, which turns the
+// ESC of a sequence into ^[, <U+001B> or U+FFFD and leaves the rest as
+// plain text; bidi and tag characters are written as <U+XXXX> (U+FFFD with
+// ControlReplacement), and invalid bytes as <FF> (U+FFFD).
+//
+// Example:
+//
+//	uniwidth.Sanitize("\x1b[31mevil\u202etxt.exe", uniwidth.ControlZero)  // "eviltxt.exe"
+//	uniwidth.Sanitize("\x1b[31mevil\u202etxt.exe", uniwidth.ControlCaret) // "^[[31mevil<U+202E>txt.exe"
+//
+// Everything else, including zero-width joiners, combining marks and
+// variation selectors, is kept.
+func Sanitize(s string, policy ControlPolicy) string {
+	var b strings.Builder
+
+	// s[start:i] is safe text not yet written to b.
+	start := 0
+	tagBase := false // the last rune can start an emoji tag sequence
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		// A valid emoji tag sequence is safe text.
+		if isTag(r) && tagBase {
+			if n := tagSequenceLen(s[i:]); n > 0 {
+				i += n
+				tagBase = false
+				continue
+			}
+		}
+
+		safe := !isControl(r) && !isBidiControl(r) && !isTag(r) &&
+			(r != utf8.RuneError || size > 1)
+		if safe {
+			tagBase = isExtendedPictographic(r) ||
+				(tagBase && (r == 0xFE0F || isEmojiModifier(r)))
+			i += size
+			continue
+		}
+		tagBase = false
+
+		if b.Cap() == 0 {
+			b.Grow(len(s))
+		}
+		b.WriteString(s[start:i])
+
+		switch {
+		case policy == ControlZero && isEscapeIntroducer(r):
+			i = skipEscapeSequence(s, i+size, r)
+			start = i
+			continue
+		case isControl(r):
+			writeControl(&b, r, policy)
+		case r == utf8.RuneError && size == 1:
+			writeInvalidByte(&b, s[i], policy)
+		default:
+			writeCodePoint(&b, r, policy)
+		}
+		i += size
+		start = i
+	}
+
+	if b.Cap() == 0 {
+		return s
+	}
+	b.WriteString(s[start:])
+	return b.String()
+}
+
+// isBidiControl returns true for the bidi embedding, override and isolate
+// controls, U+202A-U+202E and U+2066-U+2069. Their effect extends to the
+// following text, so untrusted text must not contain them.
+func isBidiControl(r rune) bool {
+	return (r >= 0x202A && r <= 0x202E) || (r >= 0x2066 && r <= 0x2069)
+}
+
+// tagSequenceLen returns the length of the tag specs (U+E0020-U+E007E) and
+// CANCEL TAG (U+E007F) at the start of s, or 0 if s does not start with at
+// least one tag spec followed by CANCEL TAG.
+func tagSequenceLen(s string) int {
+	n, specs := 0, 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case r >= 0xE0020 && r <= 0xE007E:
+			specs++
+			n += size
+		case r == 0xE007F && specs > 0:
+			return n + size
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+// isEscapeIntroducer returns true for ESC and the C1 controls that start a
+// sequence: CSI (U+009B) and the control strings DCS (U+0090), SOS
+// (U+0098), OSC (U+009D), PM (U+009E) and APC (U+009F).
+func isEscapeIntroducer(r rune) bool {
+	switch r {
+	case 0x1B, 0x90, 0x98, 0x9B, 0x9D, 0x9E, 0x9F:
+		return true
+	}
+	return false
+}
+
+// skipEscapeSequence returns the index just past the escape sequence whose
+// introducer r precedes s[i].
+func skipEscapeSequence(s string, i int, r rune) int {
+	switch r {
+	case 0x9B:
+		return skipCSI(s, i)
+	case 0x1B:
+	default:
+		return skipControlString(s, i)
+	}
+
+	if i >= len(s) {
+		return i
+	}
+	switch s[i] {
+	case '[':
+		return skipCSI(s, i+1)
+	case ']', 'P', 'X', '^', '_': // OSC, DCS, SOS, PM, APC
+		return skipControlString(s, i+1)
+	}
+
+	// ESC, intermediate bytes (0x20-0x2F), final byte (0x30-0x7E).
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2F {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x30 && s[i] <= 0x7E {
+		i++
+	}
+	return i
+}
+
+// skipCSI returns the index just past the CSI sequence whose parameters start
+// at s[i]: parameter bytes (0x30-0x3F), intermediate bytes (0x20-0x2F) and a
+// final byte (0x40-0x7E).
+func skipCSI(s string, i int) int {
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x3F {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x40 && s[i] <= 0x7E {
+		i++
+	}
+	return i
+}
+
+// skipControlString returns the index just past the control string starting
+// at s[i], which ends with BEL or ST (ESC \ or U+009C).
+func skipControlString(s string, i int) int {
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == 0x07:
+			return i + 1
+		case s[i] == 0x1B && i+1 < len(s) && s[i+1] == '\\':
+			return i + 2
+		case strings.HasPrefix(s[i:], "\u009c"):
+			return i + len("\u009c")
+		}
+	}
+	return i
+}
+
+// writeCodePoint writes the visible rendering of a non-control character
+// that is unsafe to print: <U+XXXX>, or U+FFFD for ControlReplacement.
+func writeCodePoint(b *strings.Builder, r rune, policy ControlPolicy) {
+	const hex = "0123456789ABCDEF"
+
+	switch policy {
+	case ControlCaret, ControlHex:
+		b.WriteString("<U+")
+		digits := 4
+		if r > 0xFFFF {
+			digits = 5
+		}
+		for shift := 4 * (digits - 1); shift >= 0; shift -= 4 {
+			b.WriteByte(hex[r>>shift&0xF])
+		}
+		b.WriteByte('>')
+	case ControlReplacement:
+		b.WriteRune(utf8.RuneError)
+	}
+}
+
+// writeInvalidByte writes the visible rendering of a byte that is not valid
+// UTF-8: <FF> as less shows it, or U+FFFD for ControlReplacement.
+func writeInvalidByte(b *strings.Builder, c byte, policy ControlPolicy) {
+	const hex = "0123456789ABCDEF"
+
+	switch policy {
+	case ControlCaret, ControlHex:
+		b.WriteByte('<')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0xF])
+		b.WriteByte('>')
+	case ControlReplacement:
+		b.WriteRune(utf8.RuneError)
+	}
+}
+
+// StreamSafeExtenders is the extender limit of the Unicode Stream-Safe Text
+// Format (UAX #15): no more than 30 non-starters in a row. No real text
+// needs more; longer runs are "Zalgo" text.
+const StreamSafeExtenders = 30
+
+// LimitExtenders drops the extenders past the first limit in each cluster of
+// s. Extenders are the zero-width runes a cluster absorbs after its base:
+// combining marks, variation selectors, ZWJ, ZWNJ, tag characters and
+// repeated emoji modifiers. Joined emoji and the ZWJ that joins them, the
+// skin tone of an emoji and conjoining Hangul jamo are not counted.
+//
+// A cluster with hundreds of stacked combining marks measures as width 1,
+// but smears over the lines around it and is slow to render. Extenders are
+// counted by the cluster scanner StringWidth uses, so the result has the
+// same clusters as s, each as wide as before.
+//
+// Example:
+//
+//	zalgo := "Z" + strings.Repeat("\u0336", 200)
+//	uniwidth.LimitExtenders(zalgo, uniwidth.StreamSafeExtenders) // "Z" + 30 marks
+//
+// If limit is less than 1, or no cluster exceeds it, s is returned unchanged.
+func LimitExtenders(s string, limit int) string {
+	if limit < 1 {
+		return s
+	}
+
+	var b strings.Builder
+
+	// s[last:] is not yet written to b; start is the offset of the cluster
+	// being scanned.
+	last, start := 0, 0
+	drop := func(i, size int) {
+		if b.Cap() == 0 {
+			b.Grow(len(s))
+		}
+		b.WriteString(s[last : start+i])
+		last = start + i + size
+	}
+
+	for start < len(s) {
+		// ASCII run fast path: an ASCII byte followed by another ASCII byte
+		// (or the end of the string) has no extenders.
+		if s[start] < 0x80 && (start+1 == len(s) || s[start+1] < 0x80) {
+			start++
+			continue
+		}
+
+		n, _ := scanCluster(s[start:], nil, limit, drop)
+		start += n
+	}
+
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package uniwidth
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitize(t *testing.T) {
+	const flag = "\U0001F3F4\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F" // Scotland
+
+	tests := []struct {
+		name        string
+		s           string
+		zero        string
+		caret       string
+		hex         string
+		replacement string
+	}{
+		{"empty", "", "", "", "", ""},
+		{"plain", "Hello, 世界 👍🏽", "Hello, 世界 👍🏽", "Hello, 世界 👍🏽", "Hello, 世界 👍🏽", "Hello, 世界 👍🏽"},
+		{"controls", "a\x01b\x7f\r\n", "ab", "a^Ab^?^M^J", "a<U+0001>b<U+007F><U+000D><U+000A>", "a\uFFFDb\uFFFD\uFFFD\uFFFD"},
+		{"C1 control", "a\u0085b", "ab", "aM-^Eb", "a<U+0085>b", "a\uFFFDb"},
+		{"SGR", "\x1b[1;31mred\x1b[0m", "red", "^[[1;31mred^[[0m", "<U+001B>[1;31mred<U+001B>[0m", "\uFFFD[1;31mred\uFFFD[0m"},
+		{"CSI with intermediate", "a\x1b[2 qb", "ab", "a^[[2 qb", "a<U+001B>[2 qb", "a\uFFFD[2 qb"},
+		{"C1 CSI", "a\u009b2Jb", "ab", "aM-^[2Jb", "a<U+009B>2Jb", "a\uFFFD2Jb"},
+		{"OSC 8 hyperlink", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x07", "link", "^[]8;;https://example.com^[\\link^[]8;;^G", "<U+001B>]8;;https://example.com<U+001B>\\link<U+001B>]8;;<U+0007>", "\uFFFD]8;;https://example.com\uFFFD\\link\uFFFD]8;;\uFFFD"},
+		{"window title", "\x1b]0;pwned\x07ok", "ok", "^[]0;pwned^Gok", "<U+001B>]0;pwned<U+0007>ok", "\uFFFD]0;pwned\uFFFDok"},
+		{"C1 OSC with C1 ST", "\u009d0;t\u009cok", "ok", "M-^]0;tM-^\\ok", "<U+009D>0;t<U+009C>ok", "\uFFFD0;t\uFFFDok"},
+		{"DCS", "\x1bPq#0\x1b\\ok", "ok", "^[Pq#0^[\\ok", "<U+001B>Pq#0<U+001B>\\ok", "\uFFFDPq#0\uFFFD\\ok"},
+		{"unterminated OSC", "ok\x1b]0;title", "ok", "ok^[]0;title", "ok<U+001B>]0;title", "ok\uFFFD]0;title"},
+		{"two-byte escape", "a\x1bcb", "ab", "a^[cb", "a<U+001B>cb", "a\uFFFDcb"},
+		{"charset escape", "a\x1b(0b", "ab", "a^[(0b", "a<U+001B>(0b", "a\uFFFD(0b"},
+		{"trailing ESC", "a\x1b", "a", "a^[", "a<U+001B>", "a\uFFFD"},
+		{"RLO", "evil\u202etxt.exe", "eviltxt.exe", "evil<U+202E>txt.exe", "evil<U+202E>txt.exe", "evil\uFFFDtxt.exe"},
+		{"isolates", "\u2066a\u2069", "a", "<U+2066>a<U+2069>", "<U+2066>a<U+2069>", "\uFFFDa\uFFFD"},
+		{"bidi marks kept", "a\u200fb\u200e", "a\u200fb\u200e", "a\u200fb\u200e", "a\u200fb\u200e", "a\u200fb\u200e"},
+		{"invalid byte", "a\xffb", "ab", "a<FF>b", "a<FF>b", "a\uFFFDb"},
+		{"truncated sequence", "a\xe4\xb8", "a", "a<E4><B8>", "a<E4><B8>", "a\uFFFD\uFFFD"},
+		{"surrogate", "a\xed\xa0\x80", "a", "a<ED><A0><80>", "a<ED><A0><80>", "a\uFFFD\uFFFD\uFFFD"},
+		{"U+FFFD kept", "a\uFFFD", "a\uFFFD", "a\uFFFD", "a\uFFFD", "a\uFFFD"},
+		{"emoji tag sequence", flag, flag, flag, flag, flag},
+		{"tag sequence after VS16", "\U0001F3F4\uFE0F\U000E0067\U000E007F", "\U0001F3F4\uFE0F\U000E0067\U000E007F", "\U0001F3F4\uFE0F\U000E0067\U000E007F", "\U0001F3F4\uFE0F\U000E0067\U000E007F", "\U0001F3F4\uFE0F\U000E0067\U000E007F"},
+		{"tags without base", "a\U000E0067\U000E007F", "a", "a<U+E0067><U+E007F>", "a<U+E0067><U+E007F>", "a\uFFFD\uFFFD"},
+		{"tags without cancel", "\U0001F3F4\U000E0067b", "\U0001F3F4b", "\U0001F3F4<U+E0067>b", "\U0001F3F4<U+E0067>b", "\U0001F3F4\uFFFDb"},
+		{"lone cancel tag", "\U0001F3F4\U000E007F", "\U0001F3F4", "\U0001F3F4<U+E007F>", "\U0001F3F4<U+E007F>", "\U0001F3F4\uFFFD"},
+		{"language tag", "a\U000E0001", "a", "a<U+E0001>", "a<U+E0001>", "a\uFFFD"},
+		{"removed control does not join tags", "\U0001F3F4\x01\U000E0067\U000E007F", "\U0001F3F4", "\U0001F3F4^A<U+E0067><U+E007F>", "\U0001F3F4<U+0001><U+E0067><U+E007F>", "\U0001F3F4\uFFFD\uFFFD\uFFFD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for policy, want := range []string{tt.zero, tt.caret, tt.hex, tt.replacement} {
+				if got := Sanitize(tt.s, ControlPolicy(policy)); got != want {
+					t.Errorf("Sanitize(%q, %d) = %q, want %q", tt.s, policy, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitize_NoAllocation(t *testing.T) {
+	s := "Hello, 世界 👍🏽 \U0001F3F4\U000E0067\U000E0062\U000E007F"
+	allocs := testing.AllocsPerRun(100, func() {
+		if Sanitize(s, ControlCaret) != s {
+			t.Fatal("Sanitize changed safe text")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Sanitize of safe text allocated %v times, want 0", allocs)
+	}
+}
+
+// checkSanitized reports an error if out, the result of Sanitize, still
+// contains anything Sanitize should have removed or escaped.
+func checkSanitized(t *testing.T, s, out string, policy ControlPolicy) {
+	t.Helper()
+
+	if !utf8.ValidString(out) {
+		t.Errorf("Sanitize(%q, %d) = %q, not valid UTF-8", s, policy, out)
+	}
+	for _, r := range out {
+		if isControl(r) || isBidiControl(r) || r == 0xE0001 {
+			t.Errorf("Sanitize(%q, %d) = %q, contains %U", s, policy, out, r)
+		}
+	}
+	if again := Sanitize(out, policy); again != out {
+		t.Errorf("Sanitize(Sanitize(%q, %d)) = %q, want %q", s, policy, again, out)
+	}
+}
+
+func TestLimitExtenders(t *testing.T) {
+	marks := func(n int) string { return strings.Repeat("\u0301", n) }
+	const family = "👨‍👩‍👧‍👦"
+
+	tests := []struct {
+		name  string
+		s     string
+		limit int
+		want  string
+		width int
+	}{
+		{"empty", "", 2, "", 0},
+		{"ASCII", "plain text", 2, "plain text", 10},
+		{"within limit", "e" + marks(2), 2, "e" + marks(2), 1},
+		{"over limit", "e" + marks(5), 2, "e" + marks(2), 1},
+		{"stream-safe", "Z" + marks(200), StreamSafeExtenders, "Z" + marks(30), 1},
+		{"each cluster", "a" + marks(3) + "世" + marks(4) + "b", 1, "a" + marks(1) + "世" + marks(1) + "b", 4},
+		{"no limit", "e" + marks(5), 0, "e" + marks(5), 1},
+		{"mixed extenders", "e\u0301\u200C\u0302\uFE0F\u0303", 3, "e\u0301\u200C\u0302", 1},
+		{"variation selector not counted", "☺️" + marks(3), 2, "☺️" + marks(2), 2},
+		{"keycap not counted", "1️⃣" + marks(2), 1, "1️⃣" + marks(1), 2},
+		{"ZWJ sequence within limit", family, 3, family, 2},
+		{"joining ZWJs not counted", family, 1, family, 2},
+		{"joining ZWJs past limit", family + marks(2), 1, family + marks(1), 2},
+		{"ZWJ before mark counted", "👨\u200D" + marks(2), 1, "👨\u200D", 2},
+		{"ZWJ after letter counted", "a\u200D\u200D👩", 1, "a\u200D👩", 3},
+		{"skin tone not counted", "👍🏽" + marks(2), 1, "👍🏽" + marks(1), 2},
+		{"second modifier counted", "👍🏽🏽🏽", 1, "👍🏽🏽", 2},
+		{"modifier after letter is a base", "a🏽" + marks(2), 1, "a🏽" + marks(1), 3},
+		{"modifier after dropped marks", "👍" + marks(3) + "🏽", 1, "👍" + marks(1) + "🏽", 2},
+		{"join after dropped marks", "👨‍" + marks(2) + "👩", 1, "👨‍👩", 2},
+		{"tag sequence", "🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F", 3, "🏴\U000E0067\U000E0062\U000E0073", 2},
+		{"Hangul jamo not counted", "각" + marks(2), 1, "각" + marks(1), 2},
+		{"jamo after marks counted", "ᄀ" + marks(1) + "ᅡ", 1, "ᄀ" + marks(1), 2},
+		{"control ends cluster, mark after it is a base", "e" + marks(2) + "\n" + marks(3), 1, "e" + marks(1) + "\n" + marks(2), 1},
+		{"invalid UTF-8", "e" + marks(2) + "\xff" + marks(2), 1, "e" + marks(1) + "\xff" + marks(1), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LimitExtenders(tt.s, tt.limit)
+			if got != tt.want {
+				t.Errorf("LimitExtenders(%q, %d) = %q, want %q", tt.s, tt.limit, got, tt.want)
+			}
+			if w := StringWidth(got); w != tt.width {
+				t.Errorf("StringWidth(%q) = %d, want %d", got, w, tt.width)
+			}
+			checkLimited(t, tt.s, got, tt.limit)
+		})
+	}
+}
+
+func TestLimitExtenders_NoAllocation(t *testing.T) {
+	s := "Hello, 世界 👍🏽 e\u0301\u0301 " + "👨‍👩‍👧"
+	allocs := testing.AllocsPerRun(100, func() {
+		if LimitExtenders(s, 2) != s {
+			t.Fatal("LimitExtenders changed text within the limit")
+		}
+	})
+	if allocs > 1 { // the drop closure
+		t.Errorf("LimitExtenders within the limit allocated %v times, want at most 1", allocs)
+	}
+}
+
+// checkLimited reports an error if out, the result of LimitExtenders, is not
+// s scanned with the limit minus the dropped extenders: it must have the
+// same cluster widths and be left unchanged by another pass.
+func checkLimited(t *testing.T, s, out string, limit int) {
+	t.Helper()
+
+	if limit < 1 {
+		return
+	}
+
+	want := 0
+	for i := 0; i < len(s); {
+		n, w := scanCluster(s[i:], nil, limit, nil)
+		want += w
+		i += n
+	}
+	if got := StringWidth(out); got != want {
+		t.Errorf("StringWidth(LimitExtenders(%q, %d)) = %d, want %d", s, limit, got, want)
+	}
+	if again := LimitExtenders(out, limit); again != out {
+		t.Errorf("LimitExtenders(LimitExtenders(%q, %d)) = %q, want %q", s, limit, again, out)
+	}
+}
@@ -0,0 +1,39 @@
+/*
+ * glibc-wcwidth prints the wcwidth() of every Unicode codepoint as runs of
+ * FIRST..LAST;WIDTH, the format of testdata/glibc-wcwidth-*.txt.
+ *
+ * testdata/glibc-wcwidth-2.36.txt was captured on glibc 2.36 with:
+ *
+ *	cc -o glibc-wcwidth scripts/glibc-wcwidth.c
+ *	./glibc-wcwidth
+ *
+ * A capture from another glibc goes in its own corpus file, named after the
+ * glibc version, with the header comment saying where it was captured.
+ */
+#define _XOPEN_SOURCE 700
+#include <locale.h>
+#include <stdio.h>
+#include <wchar.h>
+
+int main(void)
+{
+	unsigned int c, start = 0;
+	int w, prev = 0;
+
+	if (setlocale(LC_ALL, "C.UTF-8") == NULL) {
+		fprintf(stderr, "glibc-wcwidth: C.UTF-8 locale not available\n");
+		return 1;
+	}
+
+	for (c = 0; c <= 0x10FFFF; c++) {
+		w = wcwidth((wchar_t)c);
+		if (c > 0 && w != prev) {
+			printf("%04X..%04X;%d\n", start, c - 1, prev);
+			start = c;
+		}
+		prev = w;
+	}
+	printf("%04X..%04X;%d\n", start, 0x10FFFF, prev);
+
+	return 0;
+}
@@ -314,6 +314,53 @@ if [ $DOCS_MISSING -eq 0 ]; then
 fi
 echo ""
 
+# 12. Check the generated tables against the Unicode data
+log_info "Checking the provenance of the UCD copies in testdata..."
+RECONSTRUCTED=$(grep -l "^# Source: reconstructed" testdata/*.txt || true)
+if [ -n "$RECONSTRUCTED" ]; then
+    for f in $RECONSTRUCTED; do
+        log_error "$f was reconstructed, not trimmed from the published UCD file"
+    done
+    log_info "Run: scripts/trim-ucd.sh on a download of https://www.unicode.org/Public/16.0.0/ucd/"
+    ERRORS=$((ERRORS + 1))
+else
+    log_success "testdata UCD copies were trimmed from published files"
+fi
+
+log_info "Regenerating tables and testdata from unicode.org..."
+UCD_URL="https://www.unicode.org/Public/16.0.0/ucd"
+UCD_DIR=$(mktemp -d)
+UCD_OK=true
+for f in EastAsianWidth.txt UnicodeData.txt PropList.txt emoji/emoji-data.txt emoji/emoji-variation-sequences.txt; do
+    mkdir -p "$UCD_DIR/$(dirname "$f")"
+    curl -fsSL -o "$UCD_DIR/$f" "$UCD_URL/$f" || UCD_OK=false
+done
+GEN_TREE=$(mktemp -d)
+if [ "$UCD_OK" != true ]; then
+    log_warning "Could not download the UCD; tables_generated.go and testdata not verified"
+    WARNINGS=$((WARNINGS + 1))
+elif git worktree add --quiet --detach "$GEN_TREE" HEAD 2>/dev/null; then
+    if (cd "$GEN_TREE" && go run cmd/generate-tables/main.go -data "$UCD_DIR" >/dev/null 2>&1 &&
+        scripts/trim-ucd.sh "$UCD_DIR"); then
+        if (cd "$GEN_TREE" && git diff --quiet -- tables_generated.go testdata); then
+            log_success "tables_generated.go and testdata match the unicode.org data"
+        else
+            log_error "tables_generated.go or testdata differs from the unicode.org data"
+            log_info "Run: go generate ./... (without -data) and scripts/trim-ucd.sh, then re-run the tests"
+            ERRORS=$((ERRORS + 1))
+        fi
+    else
+        log_error "Could not regenerate the tables from the unicode.org data"
+        ERRORS=$((ERRORS + 1))
+    fi
+    git worktree remove --force "$GEN_TREE"
+else
+    log_warning "Could not create a worktree; tables_generated.go not verified"
+    WARNINGS=$((WARNINGS + 1))
+fi
+rm -rf "$UCD_DIR"
+echo ""
+
 # Summary
 echo "========================================"
 echo "  Summary"
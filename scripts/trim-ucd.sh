@@ -0,0 +1,41 @@
+#!/usr/bin/env bash
+# Writes the trimmed copies of the UCD files in testdata/ that the property
+# tests parse on their own, to check the generated tables independently of
+# cmd/generate-tables.
+#
+# Usage: scripts/trim-ucd.sh DIR
+#
+# DIR is a copy of https://www.unicode.org/Public/16.0.0/ucd/ (the layout the
+# generator's -data flag reads). The trimmed files keep the first header line
+# of each file, its Date and Version lines and its data fields; comments
+# and blank lines are removed. A Source line records where each file came
+# from: $SOURCE/FILE, where SOURCE defaults to the unicode.org URL above.
+
+set -e
+
+if [ $# -ne 1 ]; then
+    echo "usage: $0 DIR" >&2
+    exit 2
+fi
+
+DIR=$1
+OUT=$(dirname "$0")/../testdata
+SOURCE=${SOURCE:-https://www.unicode.org/Public/16.0.0/ucd}
+
+FILES=(
+    EastAsianWidth.txt
+    emoji/emoji-data.txt
+    emoji/emoji-variation-sequences.txt
+)
+
+for f in "${FILES[@]}"; do
+    {
+        head -n 1 "$DIR/$f"
+        grep -E '^# (Date|Version):' "$DIR/$f" || true
+        echo "# Source: $SOURCE/$f"
+        echo "#"
+        echo "# Trimmed copy of ucd/$f:"
+        echo "# data fields only. Regenerate with scripts/trim-ucd.sh."
+        sed -e 's/[[:space:]]*#.*$//' -e '/^[[:space:]]*$/d' "$DIR/$f"
+    } >"$OUT/$(basename "$f")"
+done
@@ -0,0 +1,74 @@
+package uniwidth
+
+import "strings"
+
+// Slice returns the part of s visible in the column window [start, end), as
+// in a horizontally scrolled view that shows columns start to end-1.
+//
+// Clusters are never split: a combining mark or other zero-width extender
+// stays with its base, and a cluster that straddles an edge of the window
+// (a wide character or emoji cut in half) is replaced by as many spaces as
+// it has columns inside the window. The result is therefore exactly
+// end-start columns wide, or narrower if s ends before end. Zero-width
+// clusters at column start are included, those at column end are not.
+//
+// Example:
+//
+//	uniwidth.Slice("ab世界cd", 1, 4) // "b世"
+//	uniwidth.Slice("ab世界cd", 1, 5) // "b世 " (界 straddles column 5)
+//	uniwidth.Slice("ab世界cd", 3, 7) // " 界c" (世 straddles column 3)
+//
+// Without options, columns are counted as StringWidth counts them; with
+// options, as StringWidthWithOptions does. A negative start is treated as 0;
+// if end <= start, the result is empty. Escape sequences are measured like
+// any other text, so remove them first (see Sanitize).
+func Slice(s string, start, end int, opts ...Option) string {
+	start = max(start, 0)
+	if end <= start {
+		return ""
+	}
+
+	// Fast path: printable ASCII has one column per byte.
+	if isASCIIOnly(s) && asciiWidth(s) == len(s) {
+		return s[min(start, len(s)):min(end, len(s))]
+	}
+
+	o := clusterOptions(opts)
+
+	// s[from:to] holds the clusters inside the window, between left and
+	// right columns of padding for the clusters straddling its edges.
+	from, to := 0, 0
+	left, right := 0, 0
+	col := 0
+	for i := 0; i < len(s) && col < end; {
+		n, w := nextCluster(s[i:], o)
+		switch next := col + w; {
+		case col < start:
+			if next > start {
+				left = min(next, end) - start
+			}
+			from, to = i+n, i+n
+		case next <= end:
+			to = i + n
+		default:
+			right = end - col
+		}
+		col += w
+		i += n
+	}
+
+	if left == 0 && right == 0 {
+		return s[from:to]
+	}
+
+	var b strings.Builder
+	b.Grow(left + (to - from) + right)
+	for range left {
+		b.WriteByte(' ')
+	}
+	b.WriteString(s[from:to])
+	for range right {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
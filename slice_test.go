@@ -0,0 +1,83 @@
+package uniwidth
+
+import "testing"
+
+func TestSlice(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		start, end int
+		want       string
+	}{
+		{"empty", "", 0, 5, ""},
+		{"ASCII", "hello world", 2, 7, "llo w"},
+		{"ASCII past end", "hello", 3, 10, "lo"},
+		{"ASCII start past end", "hello", 7, 10, ""},
+		{"negative start", "hello", -2, 2, "he"},
+		{"empty window", "hello", 3, 3, ""},
+		{"reversed window", "hello", 4, 1, ""},
+		{"ASCII with control", "a\tbc", 1, 3, "\tbc"},
+		{"CJK inside", "ab世界cd", 1, 4, "b世"},
+		{"CJK straddles right", "ab世界cd", 1, 5, "b世 "},
+		{"CJK straddles left", "ab世界cd", 3, 7, " 界c"},
+		{"CJK straddles both", "世界世", 1, 5, " 界 "},
+		{"wide cluster covers window", "世", 1, 2, " "},
+		{"narrow window inside wide", "a世b", 1, 2, " "},
+		{"emoji sequence straddles", "a👨‍👩‍👧b", 2, 4, " b"},
+		{"flag straddles", "🇯🇵🇺🇸", 1, 4, " 🇺🇸"},
+		{"combining mark stays with base", "e\u0301x", 0, 1, "e\u0301"},
+		{"combining mark kept at left edge", "ae\u0301", 1, 2, "e\u0301"},
+		{"marks of a straddling cluster dropped", "世\u0301a", 1, 3, " a"},
+		{"zero-width cluster at start kept", "a\u200Bb", 1, 2, "b"},
+		{"control at start kept", "a\x01b", 1, 2, "\x01b"},
+		{"control at end dropped", "ab\x01", 0, 2, "ab"},
+		{"window past end of string", "世界", 3, 8, " "},
+		{"invalid UTF-8", "a\xffb", 1, 3, "\xffb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slice(tt.s, tt.start, tt.end)
+			if got != tt.want {
+				t.Errorf("Slice(%q, %d, %d) = %q, want %q", tt.s, tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlice_Options(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		start, end int
+		opts       []Option
+		want       string
+	}{
+		{"ambiguous narrow", "±½x", 1, 3, []Option{WithEastAsianAmbiguous(EANarrow)}, "½x"},
+		{"ambiguous wide straddles", "±½x", 1, 3, []Option{WithEastAsianAmbiguous(EAWide)}, "  "},
+		{"ambiguous wide", "±½x", 2, 5, []Option{WithEastAsianAmbiguous(EAWide)}, "½x"},
+		{"caret control straddles", "a\x01b", 2, 4, []Option{WithControlPolicy(ControlCaret)}, " b"},
+		{"caret control inside", "a\x01b", 1, 4, []Option{WithControlPolicy(ControlCaret)}, "\x01b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slice(tt.s, tt.start, tt.end, tt.opts...)
+			if got != tt.want {
+				t.Errorf("Slice(%q, %d, %d) = %q, want %q", tt.s, tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+// checkSlice reports an error if out, the result of Slice, is not exactly as
+// wide as the part of the window that s covers.
+func checkSlice(t *testing.T, s string, start, end int, out string) {
+	t.Helper()
+
+	start = max(start, 0)
+	want := max(min(end, StringWidth(s))-start, 0)
+	if got := StringWidth(out); got != want {
+		t.Errorf("StringWidth(Slice(%q, %d, %d)) = %d (%q), want %d", s, start, end, got, out, want)
+	}
+}
@@ -0,0 +1,379 @@
+// Package table lays out rows of text in aligned columns, measuring every
+// cell in display columns with uniwidth, so CJK text, emoji and combining
+// marks line up in a monospace terminal.
+//
+// Example:
+//
+//	t := table.New(table.WithBorder(table.BorderBox), table.WithAlign(table.AlignLeft, table.AlignRight))
+//	t.SetHeader("Name", "Size")
+//	t.AddRow("報告書.pdf", "1.2 MB")
+//	t.AddRow("notes 📝.txt", "4 KB")
+//	fmt.Print(t)
+//	// ┌──────────────┬────────┐
+//	// │ Name         │   Size │
+//	// ├──────────────┼────────┤
+//	// │ 報告書.pdf   │ 1.2 MB │
+//	// │ notes 📝.txt │   4 KB │
+//	// └──────────────┴────────┘
+package table
+
+import (
+	"io"
+	"strings"
+
+	"github.com/unilibs/uniwidth"
+	"github.com/unilibs/uniwidth/internal/layout"
+)
+
+// Align is the horizontal alignment of the cells of a column.
+type Align int
+
+const (
+	// AlignLeft pads cells on the right (the default).
+	AlignLeft Align = iota
+
+	// AlignRight pads cells on the left, for numbers.
+	AlignRight
+
+	// AlignCenter splits the padding between both sides; an odd column
+	// goes to the right.
+	AlignCenter
+)
+
+// Overflow decides what happens to a cell that is wider than its column.
+type Overflow int
+
+const (
+	// OverflowTruncate cuts the cell at a cluster boundary and ends it with
+	// the ellipsis (the default).
+	OverflowTruncate Overflow = iota
+
+	// OverflowWrap breaks the cell into several lines at spaces, or at
+	// cluster boundaries inside words that are wider than the column.
+	OverflowWrap
+)
+
+// Border is the style of the lines drawn around and between cells.
+type Border int
+
+const (
+	// BorderNone separates columns with two spaces and draws no lines (the
+	// default).
+	BorderNone Border = iota
+
+	// BorderASCII draws lines with '-', '|' and '+'.
+	BorderASCII
+
+	// BorderBox draws lines with the box drawing characters ─ │ ┌ ┬ ┐ ├ ┼ ┤ └ ┴ ┘.
+	// These have East Asian Width "Ambiguous": with
+	// WithWidthOptions(uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide)) they
+	// are 2 columns wide and the layout accounts for it.
+	BorderBox
+)
+
+// glyphs are the strings a border is drawn with: horizontal and vertical
+// lines, then the corners and junctions of the top, middle and bottom rules
+// from left to right.
+type glyphs struct {
+	h, v       string
+	tl, tm, tr string
+	ml, mm, mr string
+	bl, bm, br string
+}
+
+var borderGlyphs = map[Border]glyphs{
+	BorderASCII: {"-", "|", "+", "+", "+", "+", "+", "+", "+", "+", "+"},
+	BorderBox:   {"─", "│", "┌", "┬", "┐", "├", "┼", "┤", "└", "┴", "┘"},
+}
+
+// Table is a set of rows laid out in aligned columns. Create one with New,
+// add cells with SetHeader and AddRow, and render it with Render or String.
+//
+// Rows may have different numbers of cells; missing cells are empty. A cell
+// may contain line breaks, which split it into several lines.
+type Table struct {
+	header   []string
+	rows     [][]string
+	align    []Align
+	maxWidth int
+	overflow Overflow
+	border   Border
+	ellipsis string
+	measure  layout.Measure
+}
+
+// Option configures a Table.
+type Option func(*Table)
+
+// New returns an empty table configured by opts.
+func New(opts ...Option) *Table {
+	t := &Table{ellipsis: "…"}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// WithAlign sets the alignment of the columns, in order. Columns without an
+// alignment are left-aligned.
+func WithAlign(align ...Align) Option {
+	return func(t *Table) {
+		t.align = align
+	}
+}
+
+// WithMaxWidth limits the rendered width of the table, borders included, to
+// width columns. Wider tables shrink their widest columns first, and cells
+// that no longer fit overflow as set by WithOverflow. Each column keeps at
+// least one column (two with double-width borders), so a table with many
+// columns may still exceed a very small limit. Zero means no limit.
+func WithMaxWidth(width int) Option {
+	return func(t *Table) {
+		t.maxWidth = width
+	}
+}
+
+// WithOverflow sets how cells wider than their column are shortened.
+func WithOverflow(overflow Overflow) Option {
+	return func(t *Table) {
+		t.overflow = overflow
+	}
+}
+
+// WithBorder sets the border style.
+func WithBorder(border Border) Option {
+	return func(t *Table) {
+		t.border = border
+	}
+}
+
+// WithEllipsis sets the string that ends truncated cells (default "…").
+// An empty ellipsis truncates without a marker.
+func WithEllipsis(ellipsis string) Option {
+	return func(t *Table) {
+		t.ellipsis = ellipsis
+	}
+}
+
+// WithWidthOptions measures cells, the ellipsis and the border glyphs with
+// uniwidth.StringWidthWithOptions and opts instead of uniwidth.StringWidth.
+//
+// Example:
+//
+//	// East Asian terminal: ambiguous characters such as ─ and … are wide
+//	t := table.New(
+//	    table.WithBorder(table.BorderBox),
+//	    table.WithWidthOptions(uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide)),
+//	)
+func WithWidthOptions(opts ...uniwidth.Option) Option {
+	return func(t *Table) {
+		t.measure = opts
+	}
+}
+
+// SetHeader sets the header row, which is separated from the other rows by
+// a rule when the table has borders.
+func (t *Table) SetHeader(cells ...string) {
+	t.header = cells
+}
+
+// AddRow appends a row of cells.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Render writes the table to w.
+func (t *Table) Render(w io.Writer) error {
+	_, err := io.WriteString(w, t.String())
+	return err
+}
+
+// String returns the rendered table, one line per row line, each ending in
+// a newline. An empty table renders as "".
+func (t *Table) String() string {
+	columns := len(t.header)
+	for _, row := range t.rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	g, bordered := borderGlyphs[t.border]
+	hw, vw := 1, 0
+	if bordered {
+		hw = max(t.measure.Width(g.h), 1)
+		vw = t.measure.Width(g.v)
+	}
+	widths := t.columnWidths(columns, bordered, hw, vw)
+
+	var b strings.Builder
+	if bordered {
+		t.writeRule(&b, widths, hw, g.tl, g.h, g.tm, g.tr)
+	}
+	if t.header != nil {
+		t.writeRow(&b, t.header, widths, g, bordered)
+		if bordered {
+			t.writeRule(&b, widths, hw, g.ml, g.h, g.mm, g.mr)
+		}
+	}
+	for _, row := range t.rows {
+		t.writeRow(&b, row, widths, g, bordered)
+	}
+	if bordered {
+		t.writeRule(&b, widths, hw, g.bl, g.h, g.bm, g.br)
+	}
+	return b.String()
+}
+
+// columnWidths returns the width of the content area of each column: the
+// widest line of its cells, shrunk to fit the maximum width. With borders,
+// each column plus its two padding spaces is a multiple of the width hw of
+// the horizontal line glyph, so rules line up with the cells.
+func (t *Table) columnWidths(columns int, bordered bool, hw, vw int) []int {
+	widths := make([]int, columns)
+	for _, row := range t.allRows() {
+		for c, cell := range row {
+			for line := range strings.SplitSeq(cell, "\n") {
+				widths[c] = max(widths[c], t.measure.Width(line))
+			}
+		}
+	}
+
+	// Overhead: padding and vertical lines, or the gaps between columns.
+	overhead := 2 * (columns - 1)
+	minWidth := 1
+	if bordered {
+		overhead = 2*columns + vw*(columns+1)
+		for c := range widths {
+			widths[c] = roundUp(widths[c]+2, hw) - 2
+		}
+		minWidth = roundUp(3, hw) - 2
+	}
+
+	if t.maxWidth <= 0 {
+		return widths
+	}
+	total := overhead
+	for _, w := range widths {
+		total += w
+	}
+	for total > t.maxWidth {
+		widest := 0
+		for c, w := range widths {
+			if w > widths[widest] {
+				widest = c
+			}
+		}
+		if widths[widest]-hw < minWidth {
+			break
+		}
+		widths[widest] -= hw
+		total -= hw
+	}
+	return widths
+}
+
+// allRows returns the header, if any, followed by the rows.
+func (t *Table) allRows() [][]string {
+	if t.header == nil {
+		return t.rows
+	}
+	return append([][]string{t.header}, t.rows...)
+}
+
+// roundUp rounds n up to a multiple of m.
+func roundUp(n, m int) int {
+	return (n + m - 1) / m * m
+}
+
+// writeRule writes a horizontal rule: left, then a run of h across each
+// column and its padding, separated by mid, then right.
+func (t *Table) writeRule(b *strings.Builder, widths []int, hw int, left, h, mid, right string) {
+	b.WriteString(left)
+	for c, w := range widths {
+		if c > 0 {
+			b.WriteString(mid)
+		}
+		b.WriteString(strings.Repeat(h, (w+2)/hw))
+	}
+	b.WriteString(right)
+	b.WriteByte('\n')
+}
+
+// writeRow writes the lines of a row, fitting each cell to its column.
+func (t *Table) writeRow(b *strings.Builder, row []string, widths []int, g glyphs, bordered bool) {
+	cells := make([][]string, len(widths))
+	height := 1
+	for c, w := range widths {
+		var cell string
+		if c < len(row) {
+			cell = row[c]
+		}
+		cells[c] = t.fit(cell, w)
+		height = max(height, len(cells[c]))
+	}
+
+	for i := range height {
+		var line strings.Builder
+		if bordered {
+			line.WriteString(g.v)
+		}
+		for c, w := range widths {
+			var text string
+			if i < len(cells[c]) {
+				text = cells[c][i]
+			}
+			text = t.pad(text, w, t.alignment(c))
+			if bordered {
+				line.WriteString(" " + text + " " + g.v)
+				continue
+			}
+			if c > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(text)
+		}
+		if bordered {
+			b.WriteString(line.String())
+		} else {
+			b.WriteString(strings.TrimRight(line.String(), " "))
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// fit returns the lines of cell shortened to width columns.
+func (t *Table) fit(cell string, width int) []string {
+	var lines []string
+	if t.overflow == OverflowWrap {
+		lines = t.measure.Wrap(cell, width)
+	} else {
+		lines = strings.Split(cell, "\n")
+	}
+	for i, line := range lines {
+		// Wrapped lines only overflow when one cluster is wider than the column.
+		lines[i] = t.measure.Truncate(line, width, t.ellipsis)
+	}
+	return lines
+}
+
+// alignment returns the alignment of column c.
+func (t *Table) alignment(c int) Align {
+	if c < len(t.align) {
+		return t.align[c]
+	}
+	return AlignLeft
+}
+
+// pad aligns s within width columns.
+func (t *Table) pad(s string, width int, align Align) string {
+	switch align {
+	case AlignRight:
+		return t.measure.PadLeft(s, width)
+	case AlignCenter:
+		return t.measure.Center(s, width)
+	default:
+		return t.measure.PadRight(s, width)
+	}
+}
@@ -0,0 +1,205 @@
+package table
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/unilibs/uniwidth"
+)
+
+func TestTable_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		header []string
+		rows   [][]string
+		want   string
+	}{
+		{
+			name:   "no border",
+			header: []string{"Name", "Size"},
+			rows:   [][]string{{"報告書.pdf", "1.2 MB"}, {"a.txt", "4 KB"}},
+			want: "" +
+				"Name        Size\n" +
+				"報告書.pdf  1.2 MB\n" +
+				"a.txt       4 KB\n",
+		},
+		{
+			name:   "box border",
+			opts:   []Option{WithBorder(BorderBox), WithAlign(AlignLeft, AlignRight)},
+			header: []string{"Name", "Size"},
+			rows:   [][]string{{"報告書.pdf", "1.2 MB"}, {"notes 📝.txt", "4 KB"}},
+			want: "" +
+				"┌──────────────┬────────┐\n" +
+				"│ Name         │   Size │\n" +
+				"├──────────────┼────────┤\n" +
+				"│ 報告書.pdf   │ 1.2 MB │\n" +
+				"│ notes 📝.txt │   4 KB │\n" +
+				"└──────────────┴────────┘\n",
+		},
+		{
+			name: "ASCII border without header",
+			opts: []Option{WithBorder(BorderASCII), WithAlign(AlignCenter)},
+			rows: [][]string{{"世界", "x"}, {"a"}},
+			want: "" +
+				"+------+---+\n" +
+				"| 世界 | x |\n" +
+				"|  a   |   |\n" +
+				"+------+---+\n",
+		},
+		{
+			name: "combining marks and ZWJ sequences",
+			opts: []Option{WithBorder(BorderASCII)},
+			rows: [][]string{{"café", "👨‍👩‍👧"}, {"ab", "c"}},
+			want: "" +
+				"+------+----+\n" +
+				"| café | 👨‍👩‍👧 |\n" +
+				"| ab   | c  |\n" +
+				"+------+----+\n",
+		},
+		{
+			name: "multi-line cell",
+			opts: []Option{WithBorder(BorderASCII)},
+			rows: [][]string{{"one\ntwo", "x"}},
+			want: "" +
+				"+-----+---+\n" +
+				"| one | x |\n" +
+				"| two |   |\n" +
+				"+-----+---+\n",
+		},
+		{
+			name: "max width truncates widest column",
+			opts: []Option{WithMaxWidth(20)},
+			rows: [][]string{{"the quick brown fox jumps", "世界世界世界世界"}},
+			want: "the quic…  世界世界…\n",
+		},
+		{
+			name: "max width wraps",
+			opts: []Option{WithMaxWidth(20), WithOverflow(OverflowWrap), WithBorder(BorderASCII)},
+			rows: [][]string{{"the quick brown fox jumps", "世界世界世界世界"}},
+			want: "" +
+				"+--------+---------+\n" +
+				"| the    | 世界世  |\n" +
+				"| quick  | 界世界  |\n" +
+				"| brown  | 世界    |\n" +
+				"| fox    |         |\n" +
+				"| jumps  |         |\n" +
+				"+--------+---------+\n",
+		},
+		{
+			name: "custom ellipsis",
+			opts: []Option{WithMaxWidth(8), WithEllipsis("...")},
+			rows: [][]string{{"abcdefghij"}},
+			want: "abcde...\n",
+		},
+		{
+			name: "max width not reached",
+			opts: []Option{WithMaxWidth(80)},
+			rows: [][]string{{"a", "b"}},
+			want: "a  b\n",
+		},
+		{
+			name: "empty table",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl := New(tt.opts...)
+			if tt.header != nil {
+				tbl.SetHeader(tt.header...)
+			}
+			for _, row := range tt.rows {
+				tbl.AddRow(row...)
+			}
+			if got := tbl.String(); got != tt.want {
+				t.Errorf("String() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTable_EastAsianWideBorders checks that box drawing glyphs are measured
+// with the configured East Asian setting: as 2 columns each, the rules use
+// half as many glyphs and every line has the same width.
+func TestTable_EastAsianWideBorders(t *testing.T) {
+	opt := uniwidth.WithEastAsianAmbiguous(uniwidth.EAWide)
+	tbl := New(WithBorder(BorderBox), WithWidthOptions(opt))
+	tbl.SetHeader("Name", "Size")
+	tbl.AddRow("報告書.pdf", "1.2 MB")
+	tbl.AddRow("a", "±")
+
+	want := "" +
+		"┌──────┬────┐\n" +
+		"│ Name       │ Size   │\n" +
+		"├──────┼────┤\n" +
+		"│ 報告書.pdf │ 1.2 MB │\n" +
+		"│ a          │ ±     │\n" + // ± is ambiguous, so 2 columns wide
+		"└──────┴────┘\n"
+	got := tbl.String()
+	if got != want {
+		t.Errorf("String() =\n%s\nwant:\n%s", got, want)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if w := uniwidth.StringWidthWithOptions(line, opt); w != 26 {
+			t.Errorf("line %q is %d columns wide, want 26", line, w)
+		}
+	}
+}
+
+// TestTable_MaxWidth checks that every rendered line fits the maximum width
+// for a range of limits, borders and overflow modes.
+func TestTable_MaxWidth(t *testing.T) {
+	rows := [][]string{
+		{"ID", "説明", "Status"},
+		{"1", "ファイルを開けませんでした", "❌ failed"},
+		{"2", "The quick brown fox jumps over the lazy dog", "✅ ok"},
+	}
+
+	for _, border := range []Border{BorderNone, BorderASCII, BorderBox} {
+		for _, overflow := range []Overflow{OverflowTruncate, OverflowWrap} {
+			for maxWidth := 20; maxWidth <= 60; maxWidth++ {
+				tbl := New(WithBorder(border), WithOverflow(overflow), WithMaxWidth(maxWidth))
+				for _, row := range rows {
+					tbl.AddRow(row...)
+				}
+				for _, line := range strings.Split(strings.TrimSuffix(tbl.String(), "\n"), "\n") {
+					if w := uniwidth.StringWidth(line); w > maxWidth {
+						t.Errorf("border %d, overflow %d, max %d: line %q is %d columns wide",
+							border, overflow, maxWidth, line, w)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTable_Render(t *testing.T) {
+	tbl := New()
+	tbl.AddRow("a", "b")
+
+	var buf bytes.Buffer
+	if err := tbl.Render(&buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got, want := buf.String(), "a  b\n"; got != want {
+		t.Errorf("Render() wrote %q, want %q", got, want)
+	}
+
+	if err := tbl.Render(failingWriter{}); !errors.Is(err, errWrite) {
+		t.Errorf("Render() error = %v, want %v", err, errWrite)
+	}
+}
+
+var errWrite = errors.New("write failed")
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
@@ -0,0 +1,19 @@
+//go:build !purego
+
+package tabwriter
+
+import (
+	"unsafe"
+
+	"github.com/unilibs/uniwidth"
+)
+
+// displayWidth returns the display width of text without copying it.
+func displayWidth(text []byte) int {
+	if len(text) == 0 {
+		return 0
+	}
+	// The string only lives for the duration of the call and text is not
+	// modified meanwhile.
+	return uniwidth.StringWidth(unsafe.String(&text[0], len(text)))
+}
@@ -0,0 +1,11 @@
+//go:build purego
+
+package tabwriter
+
+import "github.com/unilibs/uniwidth"
+
+// displayWidth returns the display width of text. Without unsafe, the
+// conversion copies text unless the compiler can keep it on the stack.
+func displayWidth(text []byte) int {
+	return uniwidth.StringWidth(string(text))
+}
@@ -0,0 +1,81 @@
+package uniwidth
+
+import "unicode"
+
+// Tier identifies the step of RuneWidth's tiered lookup that resolves a rune.
+//
+// It is a diagnostic aid (for example, to explain why a character measures
+// the way it does); the width itself does not depend on which tier answers.
+type Tier int
+
+const (
+	// TierASCII is the ASCII fast path (U+0000-U+007F).
+	TierASCII Tier = iota
+
+	// TierCJK is the common CJK fast path (ideographs, Hangul syllables,
+	// kana, Bopomofo and compatibility ideographs).
+	TierCJK
+
+	// TierEmoji is the common emoji fast path (emoticons, pictographs,
+	// transport symbols, miscellaneous symbols and dingbats).
+	TierEmoji
+
+	// TierZeroWidth is the zero-width check (ZWSP/ZWNJ/ZWJ/LRM/RLM,
+	// variation selectors and combining marks).
+	TierZeroWidth
+
+	// TierTable is the 3-stage table lookup that covers everything else.
+	TierTable
+)
+
+// String returns a short lowercase name for the tier.
+func (t Tier) String() string {
+	switch t {
+	case TierASCII:
+		return "ascii"
+	case TierCJK:
+		return "cjk"
+	case TierEmoji:
+		return "emoji"
+	case TierZeroWidth:
+		return "zero-width"
+	case TierTable:
+		return "table"
+	default:
+		return "unknown"
+	}
+}
+
+// RuneTier returns the tier of RuneWidth's lookup that resolves r.
+//
+// The checks mirror RuneWidth in the same order, so RuneTier(r) names the
+// first tier whose ranges contain r.
+func RuneTier(r rune) Tier {
+	switch {
+	case r < 0x80:
+		return TierASCII
+
+	case r >= 0x4E00 && r <= 0x9FFF, // CJK Unified Ideographs
+		r >= 0xAC00 && r <= 0xD7AF, // Hangul Syllables
+		r >= 0x3040 && r <= 0x312F, // Hiragana, Katakana, Bopomofo
+		r >= 0xF900 && r <= 0xFAFF: // CJK Compatibility Ideographs
+		return TierCJK
+
+	case r >= 0x1F600 && r <= 0x1F64F, // Emoticons
+		r >= 0x1F300 && r <= 0x1F5FF, // Misc Symbols and Pictographs
+		r >= 0x1F680 && r <= 0x1F6FF, // Transport and Map Symbols
+		r >= 0x1F900 && r <= 0x1F9FF, // Supplemental Symbols and Pictographs
+		r >= 0x2600 && r <= 0x26FF,   // Miscellaneous Symbols
+		r >= 0x2700 && r <= 0x27BF:   // Dingbats
+		return TierEmoji
+
+	case r >= 0x200B && r <= 0x200F, // ZWSP, ZWNJ, ZWJ, LRM, RLM
+		r >= 0xFE00 && r <= 0xFE0F,   // Variation selectors
+		r >= 0xE0100 && r <= 0xE01EF, // Variation selectors supplement
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return TierZeroWidth
+
+	default:
+		return TierTable
+	}
+}
@@ -0,0 +1,89 @@
+package uniwidth
+
+import "testing"
+
+// TestRuneTier_MatchesRuneWidth verifies for every codepoint that the tier
+// RuneTier reports is consistent with the width RuneWidth returns: the hot
+// paths return their fixed widths, and TierTable runes get exactly the
+// 3-stage table's answer.
+func TestRuneTier_MatchesRuneWidth(t *testing.T) {
+	mismatches := 0
+	const maxMismatchLog = 20
+
+	for cp := rune(0); cp <= 0x10FFFF; cp++ {
+		var ok bool
+		switch tier := RuneTier(cp); tier {
+		case TierASCII:
+			ok = cp < 0x80
+		case TierCJK, TierEmoji:
+			ok = RuneWidth(cp) == 2
+		case TierZeroWidth:
+			ok = RuneWidth(cp) == 0
+		case TierTable:
+			ok = RuneWidth(cp) == tableLookupWidth(cp)
+		}
+
+		if !ok {
+			mismatches++
+			if mismatches <= maxMismatchLog {
+				t.Errorf("RuneTier(%U) = %v, inconsistent with RuneWidth = %d", cp, RuneTier(cp), RuneWidth(cp))
+			}
+		}
+	}
+
+	if mismatches > maxMismatchLog {
+		t.Errorf("... and %d more mismatches (total: %d)", mismatches-maxMismatchLog, mismatches)
+	}
+}
+
+func TestRuneTier(t *testing.T) {
+	tests := []struct {
+		name string
+		r    rune
+		want Tier
+	}{
+		{"letter", 'a', TierASCII},
+		{"control", '\t', TierASCII},
+		{"ideograph", '世', TierCJK},
+		{"Hangul syllable", '한', TierCJK},
+		{"katakana", 'カ', TierCJK},
+		{"emoticon", '😀', TierEmoji},
+		{"misc symbol", '☺', TierEmoji},
+		{"ZWJ", 0x200D, TierZeroWidth},
+		{"VS16", 0xFE0F, TierZeroWidth},
+		{"combining acute", 0x0301, TierZeroWidth},
+		{"Latin-1", 'é', TierTable},
+		{"ambiguous", '±', TierTable},
+		{"fullwidth A", 'Ａ', TierTable},
+		{"flag letter", 0x1F1FA, TierTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RuneTier(tt.r); got != tt.want {
+				t.Errorf("RuneTier(%U) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTier_String(t *testing.T) {
+	tests := []struct {
+		tier Tier
+		want string
+	}{
+		{TierASCII, "ascii"},
+		{TierCJK, "cjk"},
+		{TierEmoji, "emoji"},
+		{TierZeroWidth, "zero-width"},
+		{TierTable, "table"},
+		{Tier(-1), "unknown"},
+		{TierTable + 1, "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.tier.String(); got != tt.want {
+			t.Errorf("Tier(%d).String() = %q, want %q", int(tt.tier), got, tt.want)
+		}
+	}
+}
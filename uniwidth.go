@@ -14,8 +14,9 @@
 package uniwidth
 
 import (
+	"strings"
 	"unicode"
-	"unsafe"
+	"unicode/utf8"
 )
 
 // RuneWidth returns the visual width of a rune in monospace terminals.
@@ -168,8 +169,14 @@ func RuneWidth(r rune) int {
 // Special handling:
 //   - ZWJ emoji sequences (👨‍👩‍👧‍👦) are treated as width 2, not the sum of parts
 //   - Emoji modifier sequences (👍🏽) are treated as width 2
-//   - Variation selectors (U+FE0E/U+FE0F) modify the width of the preceding character
-//   - Regional indicator pairs (flags) are counted as width 2, not 4
+//   - Variation selectors (U+FE0E/U+FE0F) modify the width of the preceding
+//     character if it is the base of an emoji variation sequence
+//   - Regional indicator pairs (flags) are counted as width 2, not 4;
+//     an unpaired regional indicator is width 2
+//   - Keycap sequences (1️⃣, #⃣) are treated as width 2
+//   - Emoji tag sequences (subdivision flags like 🏴󠁧󠁢󠁳󠁣󠁴󠁿) are treated as width 2
+//   - Conjoining Hangul jamo (L+V+T, as in NFD Korean) are treated as one
+//     width-2 syllable
 func StringWidth(s string) int {
 	// Short string fast path (< 8 bytes): single-pass ASCII check and width
 	// count fused into one loop. For strings shorter than 8 bytes, the SWAR
@@ -192,122 +199,249 @@ func StringWidth(s string) int {
 			return width
 		}
 	} else if isASCIIOnly(s) {
-		// SWAR fast path for longer ASCII-only strings (8+ bytes)
+		// SWAR or SIMD fast path for longer ASCII-only strings (8+ bytes)
 		return asciiWidth(s)
 	}
 
-	// Unicode path: convert to rune slice for lookahead.
-	runes := []rune(s)
-	width := 0
-
-	// Emoji sequence state tracking (forward-scan state machine):
-	//   0 = default (not in an emoji sequence)
-	//   1 = after Extended_Pictographic character (may start ZWJ/modifier sequence)
-	//   2 = after EP + (Extend*) + ZWJ (expecting joined emoji)
-	state := 0
-
-	for i := 0; i < len(runes); i++ {
-		r := runes[i]
+	return stringWidth(s, nil)
+}
 
-		// ========================================
-		// ZWJ Handling
-		// ========================================
-		// ZWJ (U+200D) after an Extended_Pictographic transitions to
-		// the "expecting joined emoji" state. ZWJ always has width 0.
-		if r == 0x200D {
-			if state == 1 {
-				state = 2
+// stringWidth sums the widths of the clusters of s. Rune widths come from
+// RuneWidth when o is nil, and from the options' width rules otherwise, so
+// StringWidth and StringWidthWithOptions share the same sequence handling.
+func stringWidth(s string, o *Options) int {
+	width := 0
+	for i := 0; i < len(s); {
+		// ASCII run fast path: an ASCII byte followed by another ASCII byte
+		// (or the end of the string) is a cluster on its own. Keycap bases
+		// and CR LF only combine with what follows, which is handled below.
+		if b := s[i]; b < 0x80 && (i+1 == len(s) || s[i+1] < 0x80) {
+			switch {
+			case b >= 0x20 && b != 0x7F:
+				width++
+			case o != nil:
+				width += o.Controls.width(rune(b))
 			}
+			i++
 			continue
 		}
 
-		// After EP + ZWJ: if next is EP, it joins (width 0).
-		// This implements the core of GB11: ExtPict Extend* ZWJ × ExtPict.
-		if state == 2 {
-			if isExtendedPictographic(r) {
-				state = 1 // Joined, still in emoji sequence
-				continue  // Width 0 — joined with preceding emoji
-			}
-			// Not a valid join target, reset state and process normally.
-			state = 0
+		n, w := nextCluster(s[i:], o)
+		width += w
+		i += n
+	}
+	return width
+}
+
+// nextCluster returns the length in bytes and the display width of the
+// cluster at the start of s, which must not be empty.
+//
+// A cluster is a base rune and everything that renders with it: the second
+// indicator of a flag pair, keycap and variation selectors, ZWJ-joined
+// emoji, emoji modifiers, tag characters, conjoining Hangul jamo, and any
+// other zero-width extenders (combining marks, ZWJ, ZWNJ). Controls are
+// clusters of their own (CR LF is one cluster).
+//
+// Emoji sequence state tracking (forward-scan state machine):
+//   - emoji: the cluster so far is an Extended_Pictographic emoji, so
+//     ZWJ + Extended_Pictographic extends it
+//   - modifiable: the last emoji of the cluster has no skin tone yet, so
+//     an emoji modifier selects one rather than repeating it
+//   - joining: the previous rune was a ZWJ after an emoji, so an
+//     Extended_Pictographic rune joins the cluster (GB11)
+func nextCluster(s string, o *Options) (n, width int) {
+	return scanCluster(s, o, 0, nil)
+}
+
+// scanCluster is nextCluster with a limit on the number of extenders (ZWJ,
+// tag characters and other zero-width runes absorbed by the extender loop)
+// in the cluster; 0 means no limit. Extenders past the limit are still part
+// of the cluster, but they leave its state unchanged, and drop, if not nil,
+// is called with the offset and size of each one. The cluster with them
+// removed therefore scans as one cluster of the same width.
+func scanCluster(s string, o *Options, limit int, drop func(i, size int)) (n, width int) {
+	r, n := decodeRune(s)
+
+	// ========================================
+	// Controls
+	// ========================================
+	// Controls never combine with neighbors (GB4/GB5), except CR LF (GB3).
+	if isControl(r) {
+		width = o.runeWidth(r)
+		if r == '\r' && n < len(s) && s[n] == '\n' {
+			n++
+			width += o.runeWidth('\n')
 		}
+		return n, width
+	}
 
-		// ========================================
-		// Emoji Modifier Handling (Skin Tones)
-		// ========================================
-		// Emoji modifiers (U+1F3FB-U+1F3FF) combine with the preceding
-		// Extended_Pictographic, contributing zero additional width.
-		if state == 1 && isEmojiModifier(r) {
-			continue // Width 0 (modifier combines with preceding emoji)
+	emoji, modifiable := false, false
+	hangul := hangulNone
+
+	switch {
+	// ========================================
+	// Regional Indicators (Flags)
+	// ========================================
+	// Regional indicators (U+1F1E6-U+1F1FF) pair from the start of a
+	// run (GB12/GB13): each pair is a flag emoji with width 2 (not 4).
+	// Consuming pairs left to right means a run of odd length leaves
+	// its last indicator unpaired (🇺🇸🇫 = flag + lone 🇫), and a lone
+	// indicator takes the configured width.
+	case isRegionalIndicator(r):
+		if next, size := decodeRune(s[n:]); isRegionalIndicator(next) {
+			n += size
+			width = 2
+		} else {
+			width = o.runeWidth(r)
 		}
 
-		// ========================================
-		// Extend Characters in Emoji Context
-		// ========================================
-		// Variation selectors and combining marks within an active emoji
-		// sequence don't add width and keep the state alive for potential
-		// ZWJ continuation.
-		if state == 1 && (r >= 0xFE00 && r <= 0xFE0F) {
-			continue // VS in emoji sequence, width 0
+	// ========================================
+	// Keycap Sequences
+	// ========================================
+	// A keycap base ([#*0-9]), an optional U+FE0F and U+20E3 COMBINING
+	// ENCLOSING KEYCAP form a single emoji with width 2 (1️⃣, #⃣).
+	case isKeycapBase(r) && isKeycapTail(s[n:]):
+		if strings.HasPrefix(s[n:], "\uFE0F") {
+			n += len("\uFE0F")
 		}
+		n += len("\u20E3")
+		width = 2
 
-		// ========================================
-		// Regional Indicator Pairs (Flags)
-		// ========================================
-		// Two consecutive regional indicators (U+1F1E6-U+1F1FF) form
-		// a flag emoji with width 2 (not 4).
-		if isRegionalIndicator(r) && i+1 < len(runes) && isRegionalIndicator(runes[i+1]) {
-			width += 2
-			i++
-			state = 0
-			continue
+	default:
+		width = o.runeWidth(r)
+
+		if width == 0 {
+			break // zero-width runes have no presentation to select
 		}
 
 		// ========================================
 		// Variation Selectors (Lookahead)
 		// ========================================
-		// Variation selectors modify the preceding character's presentation:
+		// Variation selectors modify the presentation of the bases of
+		// emoji variation sequences:
 		// - U+FE0E: Text presentation (width 1)
 		// - U+FE0F: Emoji presentation (width 2)
-		if i+1 < len(runes) {
-			next := runes[i+1]
+		// After any other rune (世, a) a selector is an ordinary zero-width
+		// extender and the width is unchanged.
+		switch next, size := decodeRune(s[n:]); {
+		case next == 0xFE0E && isEmojiVariationBase(r):
+			n += size
+			width = 1
+		case next == 0xFE0F && isEmojiVariationBase(r):
+			n += size
+			width = 2
+			emoji = isExtendedPictographic(r)
+			modifiable = emoji
+		case neverExtends(next):
+			return n, width
+		default:
+			emoji = isExtendedPictographic(r)
+			modifiable = emoji
+			hangul = hangulSyllableType(r)
+		}
+	}
 
-			if next == 0xFE0E {
-				width++
-				i++
-				state = 0
-				continue
+	// ========================================
+	// Extenders
+	// ========================================
+	joining := false
+	extenders := 0
+	for n < len(s) {
+		r, size := decodeRune(s[n:])
+
+		// A ZWJ between two emoji joins them (GB11); like the emoji it
+		// joins, it is part of the sequence rather than an extender.
+		joiner := false
+		if r == 0x200D && emoji && !joining {
+			next, _ := decodeRune(s[n+size:])
+			joiner = isExtendedPictographic(next)
+		}
+
+		// Past the limit, extenders are dropped without touching the state,
+		// so whatever follows them continues the cluster as it would
+		// without them. Joined emoji and their ZWJs, the skin tone of an
+		// emoji and Hangul jamo that join the syllable are not extenders; a
+		// repeated skin tone is.
+		if limit > 0 && extenders >= limit && !joiner && !(joining && isExtendedPictographic(r)) &&
+			(isExtender(r, o) || emoji && !joining && !modifiable && isEmojiModifier(r)) {
+			if drop != nil {
+				drop(n, size)
 			}
+			n += size
+			continue
+		}
 
-			if next == 0xFE0F {
-				width += 2
-				i++
-				if isExtendedPictographic(r) {
-					state = 1
-				} else {
-					state = 0
-				}
-				continue
+		// After EP + ZWJ: if next is EP, it joins (width 0).
+		// This implements the core of GB11: ExtPict Extend* ZWJ × ExtPict.
+		if joining {
+			joining = false
+			if isExtendedPictographic(r) {
+				modifiable = true
+				n += size
+				continue // Width 0 — joined with preceding emoji
 			}
+			// Not a valid join target: the emoji sequence ends here.
+			emoji = false
 		}
 
-		// ========================================
-		// Default: RuneWidth
-		// ========================================
-		w := RuneWidth(r)
-		width += w
+		if neverExtends(r) {
+			return n, width
+		}
+
+		switch t := hangulSyllableType(r); {
+		// ZWJ (U+200D) after an emoji expects a joined emoji.
+		case r == 0x200D:
+			if !joiner {
+				extenders++
+			}
+			joining = emoji
+			modifiable = false
+			hangul = hangulNone
 
-		// Track emoji state for ZWJ/modifier sequence detection.
-		if isExtendedPictographic(r) && w > 0 {
-			state = 1
-		} else if w > 0 {
-			state = 0
+		// Emoji modifiers (U+1F3FB-U+1F3FF) combine with the preceding
+		// Extended_Pictographic, contributing zero additional width. Only
+		// the first selects the skin tone; repeats count as extenders.
+		case emoji && isEmojiModifier(r):
+			if !modifiable {
+				extenders++
+			}
+			modifiable = false
+
+		// Leading consonants (L), vowels (V) and trailing consonants (T)
+		// conjoin into one syllable block (GB6-GB8), as do precomposed
+		// LV/LVT syllables followed by V/T jamo. The syllable takes the
+		// width of its first rune (2); every rune that joins it is width 0,
+		// so NFD Korean (ᄀ+ᅡ+ᆨ) measures the same as NFC (각).
+		case hangulJoins(hangul, t):
+			hangul = t
+
+		// Tag specs (U+E0020-U+E007E) and CANCEL TAG (U+E007F) after a tag
+		// base form one emoji (🏴 + "gbsct" + cancel = Scotland). Tag
+		// characters never render on their own, so they are absorbed
+		// whether or not the run is well-formed, keeping the emoji state.
+		case isTag(r):
+			extenders++
+			hangul = hangulNone
+
+		// Any other zero-width rune (combining marks, variation selectors,
+		// ZWNJ) extends the cluster without adding width.
+		case !isControl(r) && o.runeWidth(r) == 0:
+			extenders++
+			hangul = hangulNone
+
+		default:
+			return n, width
 		}
-		// When w == 0 (combining marks, tag characters, etc.),
-		// preserve current state to allow Extend* in GB11 pattern.
+		n += size
 	}
 
-	return width
+	return n, width
+}
+
+// isExtender reports whether r is counted as an extender when it follows the
+// base of a cluster: a ZWJ, a tag character or any other zero-width rune.
+func isExtender(r rune, o *Options) bool {
+	return r == 0x200D || isTag(r) || (!isControl(r) && o.runeWidth(r) == 0)
 }
 
 // isRegionalIndicator returns true if the rune is a regional indicator symbol.
@@ -317,199 +451,133 @@ func isRegionalIndicator(r rune) bool {
 	return r >= 0x1F1E6 && r <= 0x1F1FF
 }
 
-// isExtendedPictographic returns true if the rune has the Extended_Pictographic
-// property (Unicode 16.0 emoji-data.txt), meaning it can participate in emoji
-// ZWJ sequences. This covers all emoji ranges used in standard ZWJ sequences.
-//
-// The checks are ordered by frequency of occurrence in real-world emoji usage
-// to minimize branch mispredictions.
-func isExtendedPictographic(r rune) bool {
-	// SMP emoji blocks (U+1F000-U+1FAFF) — covers ~95% of emoji
-	// Includes: Emoticons, Pictographs, Transport, Supplemental Symbols,
-	// Symbols and Pictographs Extended-A, etc.
-	if r >= 0x1F000 && r <= 0x1FAFF {
-		return true
-	}
-
-	// BMP emoji: Misc Symbols (U+2600-U+26FF) and Dingbats (U+2700-U+27BF)
-	if r >= 0x2600 && r <= 0x27BF {
-		return true
-	}
-
-	// BMP emoji: Misc Technical (U+2300-U+23FF)
-	// Includes: ⌚⌛⏩⏪⏫⏬⏰⏳⏸⏹⏺⌨ etc.
-	if r >= 0x2300 && r <= 0x23FF {
-		return true
-	}
-
-	// Misc Symbols and Arrows (U+2B00-U+2BFF)
-	if r >= 0x2B00 && r <= 0x2BFF {
-		return true
-	}
-
-	// Arrow symbols (U+2194-U+21AA)
-	if r >= 0x2194 && r <= 0x21AA {
-		return true
-	}
-
-	// Geometric Shapes (U+25A0-U+25FF)
-	if r >= 0x25A0 && r <= 0x25FF {
-		return true
-	}
+// Hangul_Syllable_Type values used for conjoining jamo.
+const (
+	hangulNone = iota
+	hangulL    // Leading consonant (Choseong)
+	hangulV    // Vowel (Jungseong)
+	hangulT    // Trailing consonant (Jongseong)
+	hangulLV   // Precomposed syllable without trailing consonant
+	hangulLVT  // Precomposed syllable with trailing consonant
+)
 
-	// Symbols for Legacy Computing and extensions (U+1FB00-U+1FFFD)
-	if r >= 0x1FB00 && r <= 0x1FFFD {
-		return true
+// hangulSyllableType returns the Hangul_Syllable_Type of a rune, or
+// hangulNone for runes outside the Hangul jamo and syllable blocks.
+func hangulSyllableType(r rune) int {
+	if r < 0x1100 || r > 0xD7FB {
+		return hangulNone
+	}
+
+	switch {
+	case r <= 0x115F, r >= 0xA960 && r <= 0xA97C: // Hangul Jamo, Extended-A
+		return hangulL
+	case r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6: // Hangul Jamo, Extended-B
+		return hangulV
+	case r <= 0x11FF, r >= 0xD7CB: // Hangul Jamo, Extended-B
+		return hangulT
+	case r >= 0xAC00 && r <= 0xD7A3: // Hangul Syllables
+		// Every 28th syllable (starting at U+AC00) has no trailing consonant.
+		if (r-0xAC00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
 	}
+	return hangulNone
+}
 
-	// Individual Extended_Pictographic characters
-	switch r {
-	case 0x00A9, 0x00AE, // © ®
-		0x203C, 0x2049, // ‼ ⁉
-		0x2122, 0x2139, // ™ ℹ
-		0x3030, 0x303D, // 〰 〽
-		0x3297, 0x3299: // ㊗ ㊙
-		return true
+// hangulJoins reports whether a rune of Hangul_Syllable_Type next continues a
+// syllable ending in a rune of type prev (UAX #29 rules GB6, GB7 and GB8).
+func hangulJoins(prev, next int) bool {
+	switch prev {
+	case hangulL:
+		return next == hangulL || next == hangulV || next == hangulLV || next == hangulLVT
+	case hangulV, hangulLV:
+		return next == hangulV || next == hangulT
+	case hangulT, hangulLVT:
+		return next == hangulT
 	}
-
 	return false
 }
 
-// isEmojiModifier returns true if the rune is an emoji modifier (skin tone).
-// Emoji modifiers (U+1F3FB-U+1F3FF) represent Fitzpatrick skin types 1-2 through 6.
-// They combine with the preceding Extended_Pictographic character to form
-// a single emoji with a specific skin tone.
-func isEmojiModifier(r rune) bool {
-	return r >= 0x1F3FB && r <= 0x1F3FF
+// isKeycapTail reports whether s starts with the rest of a keycap sequence:
+// an optional U+FE0F followed by U+20E3 COMBINING ENCLOSING KEYCAP.
+func isKeycapTail(s string) bool {
+	const vs16, keycap = "\uFE0F", "\u20E3"
+	return strings.HasPrefix(s, keycap) || strings.HasPrefix(s, vs16+keycap)
 }
 
-// isASCIIOnly returns true if the string contains only ASCII characters (0x00-0x7F).
-//
-// Uses SWAR (SIMD Within A Register) to process 8 bytes at a time by loading
-// them into a uint64 and checking all high bits simultaneously with a single
-// AND against 0x8080808080808080. If any byte has its high bit set (>= 0x80),
-// it is non-ASCII. This works regardless of endianness because we only test
-// whether any byte has its high bit set, not which byte it is.
-//
-// Performance:
-//   - Short strings (< 8 bytes): scalar fallback, O(n) per byte
-//   - Longer strings: ~8x throughput via SWAR, O(n/8) per word + O(n%8) tail
-//   - 0 allocations in all cases
-//
-// all pointer arithmetic is bounds-checked by the loop guard (i+8 <= n, i < n).
-//
-//nolint:gosec // G103: unsafe usage is intentional for SWAR performance optimization;
-func isASCIIOnly(s string) bool {
-	n := len(s)
-	if n == 0 {
-		return true
+// neverExtends reports whether r can be ruled out as a cluster extender with
+// a couple of range checks: Latin (except the soft hyphen) and CJK symbols,
+// kana and ideographs (except the ideographic tone marks U+302A-U+302F)
+// always start a new cluster. It is true for the zero rune returned at the
+// end of the string.
+func neverExtends(r rune) bool {
+	if r < 0x0300 {
+		return r != 0x00AD
 	}
+	return r >= 0x3000 && r <= 0x9FFF && (r < 0x302A || r > 0x302F)
+}
 
-	p := unsafe.StringData(s)
+// isControl returns true for the C0 controls, DEL and the C1 controls,
+// which never combine with neighboring runes.
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7F && r <= 0x9F)
+}
 
-	// SWAR: process 8 bytes at a time
-	const asciiMask = uint64(0x8080808080808080)
-	i := 0
-	for ; i+8 <= n; i += 8 {
-		word := *(*uint64)(unsafe.Add(unsafe.Pointer(p), i))
-		if word&asciiMask != 0 {
-			return false
-		}
+// decodeRune decodes the first rune of s, returning (0, 0) for an empty
+// string and (utf8.RuneError, 1) for an invalid encoding.
+func decodeRune(s string) (rune, int) {
+	if len(s) == 0 {
+		return 0, 0
 	}
-
-	// Scalar tail: process remaining bytes (0-7)
-	for ; i < n; i++ {
-		if *(*byte)(unsafe.Add(unsafe.Pointer(p), i)) >= 0x80 {
-			return false
-		}
+	if s[0] < 0x80 {
+		return rune(s[0]), 1
 	}
+	return utf8.DecodeRuneInString(s)
+}
 
-	return true
+// isTag returns true if the rune is a tag character used in emoji tag
+// sequences: U+E0001 LANGUAGE TAG and U+E0020-U+E007F (tag specs and
+// CANCEL TAG).
+func isTag(r rune) bool {
+	return r == 0xE0001 || (r >= 0xE0020 && r <= 0xE007F)
 }
 
-// asciiWidth returns the visual width of an ASCII-only string, accounting for
-// control characters (0x00-0x1F, 0x7F) which have zero width.
-//
-// Uses SWAR to detect control characters in 8-byte chunks. If a chunk contains
-// no control characters, width += 8 directly. Otherwise, falls back to scalar
-// processing for that chunk.
-//
-// Control character detection uses Daniel Lemire's SWAR technique:
-//   - Bytes < 0x20: detected via (x - 0x2020...) & ~x & 0x8080...
-//   - Byte == 0x7F: detected via XOR with 0x7F7F... then same underflow trick
-//
-// The underflow trick works because subtracting 0x20 from a byte < 0x20 causes
-// the high bit to set (unsigned underflow), while the original byte had its high
-// bit clear. The AND with ~x isolates genuine underflows from bytes >= 0x80
-// (which cannot appear here since isASCIIOnly was already verified).
-//
-// Caller must ensure s contains only ASCII bytes (call isASCIIOnly first).
-//
-// Performance:
-//   - 0 allocations
-//   - ~8x throughput for chunks without control characters
-//
-// all pointer arithmetic is bounds-checked by the loop guards (i+8 <= n, i < n, j < 8).
+// isKeycapBase returns true if the rune can start a keycap sequence.
+// Keycap bases are '#', '*' and the ASCII digits '0'-'9' (emoji-sequences.txt
+// Emoji_Keycap_Sequence).
+func isKeycapBase(r rune) bool {
+	return r == '#' || r == '*' || (r >= '0' && r <= '9')
+}
+
+// isExtendedPictographic returns true if the rune has the Extended_Pictographic
+// property (Unicode 16.0 emoji-data.txt), meaning it can participate in emoji
+// ZWJ sequences (GB11).
 //
-//nolint:gosec // G103: unsafe usage is intentional for SWAR performance optimization;
-func asciiWidth(s string) int {
-	n := len(s)
-	if n == 0 {
-		return 0
-	}
+// Performance: O(1), 0 allocations (generated emoji property table).
+func isExtendedPictographic(r rune) bool {
+	return emojiProperties(r)&propExtendedPictographic != 0
+}
 
-	p := unsafe.StringData(s)
-	width := 0
-	i := 0
-
-	// SWAR constants for control character detection.
-	const (
-		// Broadcast 0x20 and 0x7F across all 8 bytes of a uint64.
-		lo20  = uint64(0x2020202020202020)
-		hi80  = uint64(0x8080808080808080)
-		rep7F = uint64(0x7F7F7F7F7F7F7F7F)
-		rep01 = uint64(0x0101010101010101)
-	)
-
-	// Process 8 bytes at a time
-	for ; i+8 <= n; i += 8 {
-		word := *(*uint64)(unsafe.Add(unsafe.Pointer(p), i))
-
-		// Detect bytes < 0x20 using SWAR underflow trick:
-		// (word - 0x2020...) produces underflow (sets high bit) for bytes < 0x20.
-		// &^word masks out bytes that already had high bit set (not possible for
-		// ASCII, but defensive). &hi80 extracts only the high bits.
-		hasLow := (word - lo20) & ^word & hi80
-
-		// Detect bytes == 0x7F using XOR + underflow:
-		// word ^ 0x7F7F... zeros out any 0x7F bytes. Then the zero-byte detection
-		// pattern ((v - 0x0101...) & ~v & 0x8080...) finds the zeroed positions.
-		xored := word ^ rep7F
-		has7F := (xored - rep01) & ^xored & hi80
-
-		if (hasLow | has7F) == 0 {
-			// Fast path: no control characters in this 8-byte chunk
-			width += 8
-		} else {
-			// Slow path: at least one control character, process byte by byte
-			for j := 0; j < 8; j++ {
-				b := *(*byte)(unsafe.Add(unsafe.Pointer(p), i+j))
-				if b >= 0x20 && b != 0x7F {
-					width++
-				}
-			}
-		}
-	}
+// isEmojiModifier returns true if the rune has the Emoji_Modifier property:
+// the Fitzpatrick skin tones U+1F3FB-U+1F3FF. They combine with a preceding
+// Emoji_Modifier_Base character to form a single emoji with a specific skin
+// tone.
+func isEmojiModifier(r rune) bool {
+	return emojiProperties(r)&propEmojiModifier != 0
+}
 
-	// Scalar tail: process remaining bytes (0-7)
-	for ; i < n; i++ {
-		b := *(*byte)(unsafe.Add(unsafe.Pointer(p), i))
-		if b >= 0x20 && b != 0x7F {
-			width++
-		}
-	}
+// isEmojiModifierBase returns true if the rune has the Emoji_Modifier_Base
+// property, i.e. an emoji modifier after it selects its skin tone.
+func isEmojiModifierBase(r rune) bool {
+	return emojiProperties(r)&propEmojiModifierBase != 0
+}
 
-	return width
+// isEmojiVariationBase returns true if the rune is the base of an emoji
+// variation sequence (emoji-variation-sequences.txt), i.e. U+FE0E after it
+// selects text and U+FE0F emoji presentation.
+func isEmojiVariationBase(r rune) bool {
+	return emojiProperties(r)&propEmojiVariationBase != 0
 }
 
 // tableLookupWidth performs O(1) width lookup using the 3-stage hierarchical table.
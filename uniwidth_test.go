@@ -1,6 +1,8 @@
 package uniwidth
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 	"unicode"
 )
@@ -119,6 +121,19 @@ func TestRuneWidth_ZeroWidth(t *testing.T) {
 		// Some combining marks (unicode.Mn category handled separately)
 		{"Combining acute accent", 0x0301, 0},
 		{"Combining grave accent", 0x0300, 0},
+
+		// Hangul medial vowels and final consonants (conjoining jamo)
+		{"Hangul Jungseong filler", 0x1160, 0},
+		{"Hangul Jungseong A", 0x1161, 0},
+		{"Hangul Jongseong Kiyeok", 0x11A8, 0},
+		{"Hangul Jungseong O-Yeo", 0xD7B0, 0},
+		{"Hangul Jongseong Nieun-Rieul", 0xD7CB, 0},
+
+		// Tag characters (emoji tag sequences)
+		{"Language tag", 0xE0001, 0},
+		{"Tag space", 0xE0020, 0},
+		{"Tag small letter g", 0xE0067, 0},
+		{"Cancel tag", 0xE007F, 0},
 	}
 
 	for _, tt := range tests {
@@ -223,6 +238,59 @@ func TestStringWidth_VariationSelectors(t *testing.T) {
 			s:    "☀︎❤️", // U+2600+U+FE0E + U+2764+U+FE0F
 			want: 3,      // 1 (text sun) + 2 (emoji heart)
 		},
+		// Wide emoji base with text variation
+		{
+			name: "Watch with text variation",
+			s:    "⌚︎", // ⌚ is an emoji variation base
+			want: 1,
+		},
+		// Selectors after runes that are not emoji variation bases are
+		// ordinary zero-width extenders and do not change the width.
+		{
+			name: "CJK with text variation",
+			s:    "世︎",
+			want: 2,
+		},
+		{
+			name: "CJK with emoji variation",
+			s:    "世️",
+			want: 2,
+		},
+		{
+			name: "ASCII letter with emoji variation",
+			s:    "a️",
+			want: 1,
+		},
+		{
+			name: "ASCII letter with text variation",
+			s:    "a︎",
+			want: 1,
+		},
+		{
+			name: "Non-emoji symbol with emoji variation",
+			s:    "□️", // □ is not an emoji
+			want: 1,
+		},
+		{
+			name: "Non-emoji arrow with emoji variation",
+			s:    "→️", // → is not an emoji variation base (↔ is)
+			want: 1,
+		},
+		{
+			name: "Emoji-presentation emoji without a sequence",
+			s:    "😀︎", // 😀 has no text variation sequence
+			want: 2,
+		},
+		{
+			name: "Fullwidth with emoji variation",
+			s:    "Ａ️", // Ａ
+			want: 2,
+		},
+		{
+			name: "Selectors in text",
+			s:    "a️b世︎c",
+			want: 5,
+		},
 	}
 
 	for _, tt := range tests {
@@ -285,6 +353,87 @@ func TestStringWidth_RegionalIndicators(t *testing.T) {
 	}
 }
 
+// TestStringWidth_RegionalIndicatorRuns exhaustively checks runs of 1-5
+// regional indicators in every surrounding context: indicators pair from the
+// start of the run (GB12/GB13) and an odd-length run leaves the last one
+// unpaired, measured with the configured lone width.
+func TestStringWidth_RegionalIndicatorRuns(t *testing.T) {
+	contexts := []struct {
+		name           string
+		prefix, suffix string
+		width          int // width of prefix + suffix
+	}{
+		{"bare", "", "", 0},
+		{"ASCII", "a", "b", 2},
+		{"CJK", "世", "界", 4},
+		{"emoji", "😀", "😀", 4},
+		{"combining mark", "e\u0301", "\u0301", 1},
+		{"ZWJ", "\u200D", "\u200D", 0},
+		{"VS16", "", "\uFE0F", 0},
+		{"keycap", "#\uFE0F\u20E3", "1\u20E3", 4},
+	}
+
+	// Indicators cycle through the whole A-Z range across runs.
+	next := rune(0x1F1E6)
+	indicator := func() string {
+		r := next
+		next++
+		if next > 0x1F1FF {
+			next = 0x1F1E6
+		}
+		return string(r)
+	}
+
+	for n := 1; n <= 5; n++ {
+		for _, lone := range []int{1, 2} {
+			for _, ctx := range contexts {
+				var sb strings.Builder
+				for i := 0; i < n; i++ {
+					sb.WriteString(indicator())
+				}
+				s := ctx.prefix + sb.String() + ctx.suffix
+				want := ctx.width + (n/2)*2 + (n%2)*lone
+
+				got := StringWidthWithOptions(s, WithLoneRegionalIndicatorWidth(lone))
+				if got != want {
+					t.Errorf("n=%d lone=%d %s: StringWidthWithOptions(%q) = %d, want %d", n, lone, ctx.name, s, got, want)
+				}
+				if lone == 2 {
+					if got := StringWidth(s); got != want {
+						t.Errorf("n=%d %s: StringWidth(%q) = %d, want %d", n, ctx.name, s, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestStringWidth_RegionalIndicatorsSeparated verifies that anything between
+// two indicators breaks the pair, so each side pairs independently.
+func TestStringWidth_RegionalIndicatorsSeparated(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int // with lone width 1
+	}{
+		{"flag, text, flag", "🇺🇸a🇫🇷", 5},
+		{"lone, text, lone", "🇺a🇸", 3},
+		{"odd run, text, odd run", "🇺🇸🇫 🇯🇵🇬", 7},
+		{"ZWJ between indicators", "🇺\u200D🇸", 2},
+		{"combining mark between indicators", "🇺\u0301🇸", 2},
+		{"flag + VS16 + indicator", "🇺🇸\uFE0F🇫", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StringWidthWithOptions(tt.s, WithLoneRegionalIndicatorWidth(1))
+			if got != tt.want {
+				t.Errorf("StringWidthWithOptions(%q, lone=1) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsRegionalIndicator(t *testing.T) {
 	tests := []struct {
 		name string
@@ -772,6 +921,37 @@ func TestStringWidth_EmojiModifiers(t *testing.T) {
 			s:    "Hi 👍🏽!",
 			want: 6, // H(1)+i(1)+space(1)+thumbs(2)+!(1)
 		},
+		// A modifier extends the cluster of any emoji, and only of an emoji
+		{
+			name: "Modifier after non-base emoji",
+			s:    "😀🏽",
+			want: 2,
+		},
+		{
+			name: "Modifier after heart",
+			s:    "❤🏽",
+			want: 2,
+		},
+		{
+			name: "Second modifier after modified emoji",
+			s:    "👍🏽🏽",
+			want: 2,
+		},
+		{
+			name: "Modifier after letter",
+			s:    "a🏽",
+			want: 3, // a(1) + lone modifier(2)
+		},
+		{
+			name: "Modifier after CJK",
+			s:    "世🏽",
+			want: 4, // 世(2) + lone modifier(2)
+		},
+		{
+			name: "Modifier after control",
+			s:    "\x01🏽",
+			want: 2, // control(0) + lone modifier(2)
+		},
 	}
 
 	for _, tt := range tests {
@@ -809,6 +989,17 @@ func TestStringWidth_ZWJEdgeCases(t *testing.T) {
 			s:    "😀\u200Da",
 			want: 3, // emoji(2) + ZWJ(0) + a(1)
 		},
+		// ZWJ after a symbol that is not Extended_Pictographic
+		{
+			name: "Diameter sign + ZWJ + emoji",
+			s:    "⌀\u200D😀",
+			want: 3, // ⌀(1) + ZWJ(0) + emoji(2)
+		},
+		{
+			name: "White square + ZWJ + emoji",
+			s:    "□\u200D😀",
+			want: 3, // □(1) + ZWJ(0) + emoji(2)
+		},
 		// Multiple ZWJs without emoji
 		{
 			name: "Multiple standalone ZWJs",
@@ -854,7 +1045,7 @@ func TestStringWidth_ZWJEdgeCases(t *testing.T) {
 		{
 			name: "Keycap 1",
 			s:    "1\uFE0F\u20E3",
-			want: 2, // 1+VS16 → width 2, combining keycap → width 0
+			want: 2, // keycap sequence → width 2
 		},
 	}
 
@@ -869,6 +1060,391 @@ func TestStringWidth_ZWJEdgeCases(t *testing.T) {
 	}
 }
 
+// TestStringWidth_KeycapSequences verifies that all 12 keycap sequences
+// (emoji-sequences.txt Emoji_Keycap_Sequence), fully-qualified and unqualified,
+// are measured as a single width-2 emoji by both string APIs.
+func TestStringWidth_KeycapSequences(t *testing.T) {
+	for _, base := range "#*0123456789" {
+		for _, form := range []struct {
+			name string
+			s    string
+		}{
+			{"qualified", string(base) + "\uFE0F\u20E3"},
+			{"unqualified", string(base) + "\u20E3"},
+		} {
+			t.Run(string(base)+" "+form.name, func(t *testing.T) {
+				if got := StringWidth(form.s); got != 2 {
+					t.Errorf("StringWidth(%q) = %d, want 2", form.s, got)
+				}
+				if got := StringWidthWithOptions(form.s); got != 2 {
+					t.Errorf("StringWidthWithOptions(%q) = %d, want 2", form.s, got)
+				}
+				if got := StringWidthWithOptions(form.s, WithEastAsianAmbiguous(EAWide)); got != 2 {
+					t.Errorf("StringWidthWithOptions(%q, EAWide) = %d, want 2", form.s, got)
+				}
+			})
+		}
+	}
+}
+
+func TestStringWidth_KeycapEdgeCases(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"keycap in text", "Press 1\uFE0F\u20E3 now", 12},      // Press(5) + space + keycap(2) + space + now(3)
+		{"adjacent keycaps", "1\uFE0F\u20E32\u20E3#\u20E3", 6}, // 3 keycaps × 2
+		{"digits before keycap", "10\uFE0F\u20E3", 3},          // 1(1) + keycap(2)
+		{"non-keycap base", "a\u20E3", 1},                      // a(1) + enclosing keycap(0)
+		{"lone enclosing keycap", "\u20E3", 0},
+		{"digit + VS16 only", "1\uFE0F", 2},
+		{"digit + VS15 + keycap", "1\uFE0E\u20E3", 1}, // text presentation, not a keycap emoji
+		{"keycap + trailing ZWJ", "1\uFE0F\u20E3\u200D😀", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.s); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStringWidth_EmojiTagSequences verifies the RGI_Emoji_Tag_Sequence
+// entries of emoji-sequences.txt (Unicode 16.0) are measured as width 2.
+func TestStringWidth_EmojiTagSequences(t *testing.T) {
+	// code_point(s) ; type_field ; description, as in emoji-sequences.txt
+	rgi := []string{
+		"1F3F4 E0067 E0062 E0065 E006E E0067 E007F ; RGI_Emoji_Tag_Sequence ; flag: England",
+		"1F3F4 E0067 E0062 E0073 E0063 E0074 E007F ; RGI_Emoji_Tag_Sequence ; flag: Scotland",
+		"1F3F4 E0067 E0062 E0077 E006C E0073 E007F ; RGI_Emoji_Tag_Sequence ; flag: Wales",
+	}
+
+	for _, line := range rgi {
+		fields := strings.Split(line, ";")
+		name := strings.TrimSpace(fields[2])
+
+		var sb strings.Builder
+		for _, cp := range strings.Fields(fields[0]) {
+			r, err := strconv.ParseUint(cp, 16, 32)
+			if err != nil {
+				t.Fatalf("bad code point %q in %q", cp, line)
+			}
+			sb.WriteRune(rune(r))
+		}
+		seq := sb.String()
+
+		t.Run(name, func(t *testing.T) {
+			if got := StringWidth(seq); got != 2 {
+				t.Errorf("StringWidth(%q) = %d, want 2", seq, got)
+			}
+			if got := StringWidthWithOptions(seq, WithEastAsianAmbiguous(EAWide)); got != 2 {
+				t.Errorf("StringWidthWithOptions(%q, EAWide) = %d, want 2", seq, got)
+			}
+			if got := StringWidth("[" + seq + "]"); got != 4 {
+				t.Errorf("StringWidth(%q) = %d, want 4", "["+seq+"]", got)
+			}
+			if got := StringWidth(seq + seq); got != 4 {
+				t.Errorf("StringWidth(%q) = %d, want 4", seq+seq, got)
+			}
+		})
+	}
+}
+
+// TestStringWidth_MalformedTagRuns verifies that tag characters outside a
+// well-formed emoji tag sequence never add width.
+func TestStringWidth_MalformedTagRuns(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"missing cancel tag", "🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074", 2},
+		{"cancel tag only", "🏴\U000E007F", 2},
+		{"tags without base", "\U000E0067\U000E0062\U000E007F", 0},
+		{"lone cancel tag", "\U000E007F", 0},
+		{"tags after ASCII", "a\U000E0067\U000E0062\U000E007F", 1},
+		{"tags after CJK", "世\U000E0067\U000E007F", 2},
+		{"tags in text", "ab\U000E0067\U000E0062cd", 4},
+		{"language tag", "\U000E0001\U000E006A\U000E0061abc", 3},
+		{"tags then ZWJ sequence", "🏴\U000E0067\U000E007F\u200D😀", 2},
+		{"tags after keycap", "1\uFE0F\u20E3\U000E0067\U000E007F", 2},
+		{"tags after flag", "🇺🇸\U000E0067\U000E007F", 2},
+		{"tags after ZWJ", "😀\u200D\U000E0067😀", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.s); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+			if got := StringWidthWithOptions(tt.s); got != tt.want {
+				t.Errorf("StringWidthWithOptions(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStringWidth_HangulJamo verifies that conjoining jamo sequences are
+// measured as one width-2 syllable.
+func TestStringWidth_HangulJamo(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"L+V (NFD 가)", "\u1100\u1161", 2},
+		{"L+V+T (NFD 각)", "\u1100\u1161\u11A8", 2},
+		{"NFD word 한국어", "\u1112\u1161\u11AB\u1100\u116E\u11A8\u110B\u1165", 6},
+		{"NFD file name", "\u1109\u1161\u110C\u1175\u11AB.jpg", 8}, // 사진.jpg
+		{"LV + T (가 + ᆨ)", "\uAC00\u11A8", 2},
+		{"LVT + T (각 + ᆨ)", "\uAC01\u11A8", 2},
+		{"LV + V", "\uAC00\u1161", 2},
+		{"old Korean L+L+V", "\u1100\u1100\u1161", 2},
+		{"old Korean L+V+V+T+T", "\u1100\u1161\u1175\u11A8\u11BA", 2},
+		{"Extended-A L + V", "\uA960\u1161", 2},
+		{"L + Extended-B V + T", "\u1100\uD7B0\uD7CB", 2},
+		{"choseong filler + V", "\u115F\u1161", 2},
+		{"lone L", "\u1100", 2},
+		{"lone V", "\u1161", 0},
+		{"lone T", "\u11A8", 0},
+		{"L + L (no vowel)", "\u1100\u1100", 2},
+		{"two syllables", "\u1100\u1161\u1100\u1161", 4},
+		{"LVT + V starts nothing", "\uAC01\u1161", 2}, // V cannot follow LVT; zero width on its own
+		{"syllable + ASCII", "\u1100\u1161a", 3},
+		{"ASCII + V", "a\u1161", 1},
+		{"combining mark breaks syllable", "\u1100\u0301\u1100\u1161", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.s); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+			if got := StringWidthWithOptions(tt.s, WithEastAsianAmbiguous(EAWide)); got != tt.want {
+				t.Errorf("StringWidthWithOptions(%q, EAWide) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStringWidth_HangulNFDMatchesNFC decomposes every precomposed Hangul
+// syllable into L+V(+T) jamo and checks both forms measure width 2.
+func TestStringWidth_HangulNFDMatchesNFC(t *testing.T) {
+	const (
+		sBase, lBase, vBase, tBase = 0xAC00, 0x1100, 0x1161, 0x11A7
+		vCount, tCount             = 21, 28
+		nCount                     = vCount * tCount
+		sCount                     = 11172
+	)
+
+	for sIndex := rune(0); sIndex < sCount; sIndex++ {
+		nfc := string(sBase + sIndex)
+		nfd := string([]rune{lBase + sIndex/nCount, vBase + (sIndex%nCount)/tCount})
+		if tIndex := sIndex % tCount; tIndex != 0 {
+			nfd += string(tBase + tIndex)
+		}
+
+		if got := StringWidth(nfc); got != 2 {
+			t.Errorf("StringWidth(%q) = %d, want 2", nfc, got)
+		}
+		if got := StringWidth(nfd); got != 2 {
+			t.Errorf("StringWidth(NFD %q = %U) = %d, want 2", nfc, []rune(nfd), got)
+		}
+	}
+}
+
+// TestStringWidth_ClusterScanner covers the cases the cluster scanner measures
+// differently from the per-rune state machine it replaced: controls end a
+// cluster, a variation selector only changes the width of a visible base, and
+// a ZWJ only joins the emoji directly after it.
+func TestStringWidth_ClusterScanner(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		want   int
+		before int // width under the per-rune state machine
+	}{
+		{"VS16 after control", "\t\uFE0F", 0, 2},
+		{"VS16 alone after mark", "\u0301\uFE0F", 0, 2},
+		{"VS16 after base and mark", "e\u0301\uFE0F", 1, 3},
+		{"VS15 after base and mark", "e\u0301\uFE0E", 1, 2},
+		{"control between emoji and modifier", "👍\x1b🏽", 4, 2},
+		{"control between emoji and VS16", "😀\x1b\uFE0F", 2, 4},
+		{"second ZWJ", "👨\u200D\u200D👩", 4, 2},
+		{"ZWJ after control", "👨\x1b\u200D👩", 4, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.s); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d (was %d)", tt.s, got, tt.want, tt.before)
+			}
+			if got := StringWidthWithOptions(tt.s); got != tt.want {
+				t.Errorf("StringWidthWithOptions(%q) = %d, want %d (was %d)", tt.s, got, tt.want, tt.before)
+			}
+		})
+	}
+}
+
+// TestStringWidth_StateMachineConformance checks the cluster scanner against
+// the rune state machine it replaced, for every string of up to four runes
+// from an alphabet of runes that each drive a different rule. The two may
+// only differ where a control or a second ZWJ ends an emoji sequence, the
+// changes listed in TestStringWidth_ClusterScanner.
+func TestStringWidth_StateMachineConformance(t *testing.T) {
+	alphabet := []rune{
+		'a', '#', '\t', 0x85, // ASCII, keycap base, C0 and C1 controls
+		'世', 0x2300, 0xA9, // wide, narrow symbol, text-default emoji
+		0x1100, 0x1161, 0x11A8, 0xAC00, // Hangul L, V, T and LV
+		0x0301, 0x200D, 0xFE0E, 0xFE0F, 0x20E3, // extenders
+		0x1F600, 0x1F44D, 0x2764, 0x1F3FD, // emoji, modifier base, modifier
+		0x1F1FA, 0x1F1F8, // regional indicators
+		0x1F3F4, 0xE0067, 0xE007F, // tag base, tag spec, cancel tag
+	}
+
+	var check func(runes []rune)
+	check = func(runes []rune) {
+		if len(runes) > 0 {
+			s := string(runes)
+			got, want := StringWidth(s), stateMachineWidth(s)
+			changed := strings.ContainsFunc(s, isControl) || strings.Contains(s, "\u200D\u200D")
+			if got != want && !changed {
+				t.Errorf("StringWidth(%+q) = %d, state machine = %d", s, got, want)
+			}
+		}
+		if len(runes) == 4 {
+			return
+		}
+		for _, r := range alphabet {
+			check(append(runes, r))
+		}
+	}
+	check(nil)
+}
+
+// stateMachineWidth is the rune state machine StringWidth ran before the
+// cluster scanner, with variation selectors applied only to the bases of
+// emoji variation sequences as the scanner does.
+func stateMachineWidth(s string) int {
+	runes := []rune(s)
+	width := 0
+	hangul := hangulNone
+
+	// 0 = not in an emoji sequence, 1 = after Extended_Pictographic,
+	// 2 = after Extended_Pictographic + ZWJ.
+	state := 0
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		prevHangul := hangul
+		hangul = hangulNone
+
+		if r == 0x200D {
+			if state == 1 {
+				state = 2
+			}
+			continue
+		}
+		if state == 2 {
+			if isExtendedPictographic(r) {
+				state = 1
+				continue
+			}
+			state = 0
+		}
+		if isTag(r) {
+			continue
+		}
+		if state == 1 && (isEmojiModifier(r) || r >= 0xFE00 && r <= 0xFE0F) {
+			continue
+		}
+
+		if isRegionalIndicator(r) {
+			if i+1 < len(runes) && isRegionalIndicator(runes[i+1]) {
+				i++
+			}
+			width += 2
+			state = 0
+			continue
+		}
+		if t := hangulSyllableType(r); t != hangulNone {
+			hangul = t
+			if hangulJoins(prevHangul, t) {
+				continue
+			}
+		}
+		if isKeycapBase(r) {
+			j := i + 1
+			if j < len(runes) && runes[j] == 0xFE0F {
+				j++
+			}
+			if j < len(runes) && runes[j] == 0x20E3 {
+				width += 2
+				i = j
+				state = 0
+				continue
+			}
+		}
+		if i+1 < len(runes) && isEmojiVariationBase(r) {
+			switch runes[i+1] {
+			case 0xFE0E:
+				width++
+				i++
+				state = 0
+				continue
+			case 0xFE0F:
+				width += 2
+				i++
+				state = 0
+				if isExtendedPictographic(r) {
+					state = 1
+				}
+				continue
+			}
+		}
+
+		w := RuneWidth(r)
+		width += w
+		if isExtendedPictographic(r) && w > 0 {
+			state = 1
+		} else if w > 0 {
+			state = 0
+		}
+	}
+	return width
+}
+
+// TestStringWidth_NoAllocation verifies that measuring non-ASCII text does
+// not allocate.
+func TestStringWidth_NoAllocation(t *testing.T) {
+	s := "Hello, 世界! 👨\u200D👩\u200D👧 e\u0301 🇯🇵 1\uFE0F\u20E3"
+	if n := testing.AllocsPerRun(100, func() { StringWidth(s) }); n != 0 {
+		t.Errorf("StringWidth allocates %v times, want 0", n)
+	}
+}
+
+// TestNeverExtends verifies the cluster fast exit against the full extender
+// rules for every codepoint it accepts: such a rune must never be zero-width
+// (with or without options), a control, a conjoining jamo, a tag or an emoji
+// modifier.
+func TestNeverExtends(t *testing.T) {
+	for cp := rune(0); cp <= 0x10FFFF; cp++ {
+		if !neverExtends(cp) || cp == 0 {
+			continue
+		}
+		if isControl(cp) {
+			continue // controls end the cluster on their own
+		}
+		if RuneWidth(cp) == 0 || runeWidthInternal(cp) == 0 ||
+			hangulSyllableType(cp) != hangulNone || isTag(cp) || isEmojiModifier(cp) {
+			t.Errorf("neverExtends(%U) = true, but %U can extend a cluster", cp, cp)
+		}
+	}
+}
+
 func TestIsExtendedPictographic(t *testing.T) {
 	tests := []struct {
 		name string
@@ -973,46 +1549,65 @@ func TestRuneWidth_UncommonRanges(t *testing.T) {
 // isExtendedPictographic — exhaustive branch coverage for all Unicode ranges
 // =============================================================================
 
-func TestIsExtendedPictographic_AllRanges(t *testing.T) {
+// TestIsExtendedPictographic_BlockEdges checks Extended_Pictographic at the
+// edges of the blocks that contain it. Only parts of most of these blocks
+// are pictographic: ⌀ (U+2300) and □ (U+25A1) must not start ZWJ joins.
+func TestIsExtendedPictographic_BlockEdges(t *testing.T) {
 	tests := []struct {
 		name string
 		r    rune
 		want bool
 	}{
-		// Range: Misc Symbols and Arrows (U+2B00-U+2BFF)
-		{"Misc Symbols/Arrows start U+2B00", 0x2B00, true},
+		// Misc Symbols and Arrows (U+2B00-U+2BFF): only ⬅⬆⬇⬛⬜⭐⭕
+		{"North east white arrow U+2B00", 0x2B00, false},
+		{"Leftwards black arrow U+2B05", 0x2B05, true},
 		{"Up arrow U+2B06", 0x2B06, true},
 		{"Star U+2B50", 0x2B50, true},
-		{"Misc Symbols/Arrows end U+2BFF", 0x2BFF, true},
-		{"Below Misc Symbols/Arrows U+2AFF", 0x2AFF, false},
+		{"Heavy large circle U+2B55", 0x2B55, true},
+		{"Misc Symbols/Arrows end U+2BFF", 0x2BFF, false},
 
-		// Range: Arrow symbols (U+2194-U+21AA)
+		// Arrows (U+2190-U+21FF): only ↔-↙ and ↩↪
+		{"Below arrow range U+2193", 0x2193, false},
 		{"Left-right arrow U+2194", 0x2194, true},
+		{"South west arrow U+2199", 0x2199, true},
+		{"Leftwards two-headed arrow U+219E", 0x219E, false},
 		{"Rightwards arrow with hook U+21AA", 0x21AA, true},
-		{"Mid arrow range U+219E", 0x219E, true},
-		{"Below arrow range U+2193", 0x2193, false},
-
-		// Range: Geometric Shapes (U+25A0-U+25FF)
-		{"Black square U+25A0", 0x25A0, true},
-		{"White circle U+25CB", 0x25CB, true},
-		{"Geometric end U+25FF", 0x25FF, true},
-		{"Below geometric range U+259F", 0x259F, false},
-
-		// Range: Legacy Computing (U+1FB00-U+1FFFD)
-		{"Legacy Computing start U+1FB00", 0x1FB00, true},
-		{"Legacy Computing mid U+1FC00", 0x1FC00, true},
-		{"Legacy Computing end U+1FFFD", 0x1FFFD, true},
-		{"Above legacy range U+1FFFE", 0x1FFFE, false},
 
-		// Verify existing ranges still work
-		{"SMP emoji start U+1F000", 0x1F000, true},
+		// Geometric Shapes (U+25A0-U+25FF): only ▪▫▶◀◻◼◽◾
+		{"Black square U+25A0", 0x25A0, false},
+		{"White square U+25A1", 0x25A1, false},
+		{"Black small square U+25AA", 0x25AA, true},
+		{"Play button U+25B6", 0x25B6, true},
+		{"White circle U+25CB", 0x25CB, false},
+		{"Black medium small square U+25FE", 0x25FE, true},
+		{"Geometric end U+25FF", 0x25FF, false},
+
+		// Misc Technical (U+2300-U+23FF): only ⌚⌛⌨⏏⏩-⏳⏸-⏺ and ⎈
+		{"Diameter sign U+2300", 0x2300, false},
+		{"Watch U+231A", 0x231A, true},
+		{"Keyboard U+2328", 0x2328, true},
+		{"Helm symbol U+2388", 0x2388, true},
+		{"Record button U+23FA", 0x23FA, true},
+		{"Power symbol U+23FB", 0x23FB, false},
+		{"Misc Technical end U+23FF", 0x23FF, false},
+
+		// SMP pictographs, including reserved codepoints set aside for emoji
+		{"Mahjong tile east wind U+1F000", 0x1F000, true},
+		{"Playing card back U+1F0A0", 0x1F0A0, true},
+		{"Regional indicator A U+1F1E6", 0x1F1E6, false},
+		{"Emoji modifier U+1F3FB", 0x1F3FB, false},
 		{"SMP emoji end U+1FAFF", 0x1FAFF, true},
+		{"Legacy Computing start U+1FB00", 0x1FB00, false},
+		{"Reserved U+1FC00", 0x1FC00, true},
+		{"Reserved end U+1FFFD", 0x1FFFD, true},
+		{"Above reserved range U+1FFFE", 0x1FFFE, false},
+
+		// BMP blocks
 		{"Misc Symbols start U+2600", 0x2600, true},
 		{"Dingbats end U+27BF", 0x27BF, true},
-		{"Misc Technical start U+2300", 0x2300, true},
-		{"Misc Technical end U+23FF", 0x23FF, true},
+		{"After dingbats U+27C0", 0x27C0, false},
 
-		// Individual EP characters (switch statement)
+		// Individual Extended_Pictographic characters
 		{"Double exclamation U+203C", 0x203C, true},
 		{"Exclamation question U+2049", 0x2049, true},
 		{"Info source U+2139", 0x2139, true},
@@ -1021,14 +1616,15 @@ func TestIsExtendedPictographic_AllRanges(t *testing.T) {
 		{"Circled congratulation U+3297", 0x3297, true},
 		{"Circled secret U+3299", 0x3299, true},
 
-		// Negatives: characters in gaps between EP ranges
-		{"After arrow, before misc tech U+21AB", 0x21AB, false},
+		// Negatives
 		{"Control Pictures block U+2400", 0x2400, false},
 		{"Box Drawing block U+2500", 0x2500, false},
-		{"After dingbats U+27C0", 0x27C0, false},
 		{"BMP high U+FFFF", 0xFFFF, false},
 		{"Regular Latin", 'Z', false},
+		{"Keycap base", '#', false},
 		{"Null character", 0x0000, false},
+		{"Negative rune", -1, false},
+		{"Beyond max rune", 0x110000, false},
 	}
 
 	for _, tt := range tests {
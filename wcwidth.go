@@ -0,0 +1,84 @@
+package uniwidth
+
+import "unicode/utf8"
+
+// Wcwidth returns the width of a rune exactly as glibc's wcwidth() reports it
+// in a UTF-8 locale.
+//
+// Unlike RuneWidth, Wcwidth follows POSIX semantics:
+//   - -1 for non-printable characters (C0/C1 controls, DEL, unassigned
+//     codepoints, surrogates, U+2028 and U+2029) and for invalid runes
+//   - 0 for NUL, combining marks, format characters (except U+00AD and
+//     prepended concatenation marks) and Hangul medial/final jamo
+//   - 2 for East Asian Wide and Fullwidth characters
+//   - 1 for everything else, including ambiguous characters
+//
+// Use it when output must line up with C programs sharing the same terminal.
+// For measuring text as modern terminals render it, use RuneWidth.
+//
+// Performance: O(1), 0 allocations (3-stage table lookup).
+func Wcwidth(r rune) int {
+	// ASCII fast path
+	if r < 0x80 {
+		if r == 0 {
+			return 0
+		}
+		// C0 controls, DEL and negative (invalid) runes are non-printable
+		if r < 0x20 || r == 0x7F {
+			return -1
+		}
+		return 1
+	}
+
+	if r > utf8.MaxRune {
+		return -1
+	}
+
+	cp := uint32(r) //nolint:gosec // G115: r is within 0x80–0x10FFFF here
+	rootIdx := wcwidthRoot[cp>>13]
+	midIdx := wcwidthMiddle[rootIdx][cp>>7&0x3F]
+	packed := wcwidthLeaves[midIdx][cp>>2&0x1F]
+	width := (packed >> (2 * (cp & 0x03))) & 0x03
+	if width == 3 {
+		return -1 // non-printable
+	}
+	return int(width)
+}
+
+// Wcswidth returns the width of a string exactly as glibc's wcswidth() reports
+// it for the same characters in a UTF-8 locale.
+//
+// The result is the plain sum of Wcwidth over the runes of s: no grapheme
+// clusters, ZWJ sequences, flags or variation selectors are merged. If any
+// rune is non-printable, or s contains invalid UTF-8, Wcswidth returns -1.
+//
+// For measuring text as modern terminals render it, use StringWidth.
+func Wcswidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		// ASCII fast path
+		if b := s[i]; b < 0x80 {
+			if (b < 0x20 && b != 0) || b == 0x7F {
+				return -1
+			}
+			if b != 0 {
+				width++
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return -1 // invalid UTF-8 cannot be converted to wide characters
+		}
+
+		w := Wcwidth(r)
+		if w < 0 {
+			return -1
+		}
+		width += w
+		i += size
+	}
+	return width
+}
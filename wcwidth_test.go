@@ -0,0 +1,193 @@
+package uniwidth
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// loadWcwidthCorpus reads a testdata corpus of FIRST..LAST;WIDTH runs and
+// returns the expected wcwidth() of every codepoint U+0000..U+10FFFF.
+func loadWcwidthCorpus(t *testing.T, name string) []int {
+	t.Helper()
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("open corpus: %v", err)
+	}
+	defer f.Close()
+
+	want := make([]int, 0x110000)
+	covered := 0
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rng, width, ok := strings.Cut(line, ";")
+		first, last, ok2 := strings.Cut(rng, "..")
+		if !ok || !ok2 {
+			t.Fatalf("malformed corpus line %q", line)
+		}
+		lo, err1 := strconv.ParseUint(first, 16, 32)
+		hi, err2 := strconv.ParseUint(last, 16, 32)
+		w, err3 := strconv.Atoi(width)
+		if err1 != nil || err2 != nil || err3 != nil || lo > hi || hi > 0x10FFFF {
+			t.Fatalf("malformed corpus line %q", line)
+		}
+
+		for cp := lo; cp <= hi; cp++ {
+			want[cp] = w
+			covered++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read corpus: %v", err)
+	}
+
+	if covered != len(want) {
+		t.Fatalf("corpus covers %d codepoints, want %d", covered, len(want))
+	}
+	return want
+}
+
+// unicode16WidthChanges are the codepoints assigned in Unicode 14.0.0 whose
+// wcwidth() changed in Unicode 15.0.0 to 16.0.0.
+var unicode16WidthChanges = []runeRange{
+	{0x2630, 0x2637},   // trigrams: East_Asian_Width N to W (16.0)
+	{0x268A, 0x268F},   // monograms and digrams: N to W (16.0)
+	{0x1171E, 0x1171E}, // AHOM CONSONANT SIGN MEDIAL RA: Mn to Mc (16.0)
+	{0x1D300, 0x1D356}, // Tai Xuan Jing symbols: N to W (16.0)
+	{0x1D360, 0x1D376}, // counting rod numerals: N to W (16.0)
+}
+
+// unicode16NewCharacters is the number of characters Unicode 15.0.0 (4,489),
+// 15.1.0 (627) and 16.0.0 (5,185) added.
+const unicode16NewCharacters = 4489 + 627 + 5185
+
+// TestWcwidth_Glibc236 verifies Wcwidth against the wcwidth() of a real glibc,
+// 2.36, which carries Unicode 14.0.0 data. Every codepoint must match, except
+// the characters assigned since, which glibc 2.36 reports as -1, and the
+// documented width changes since.
+func TestWcwidth_Glibc236(t *testing.T) {
+	want := loadWcwidthCorpus(t, "testdata/glibc-wcwidth-2.36.txt")
+
+	mismatches, assigned := 0, 0
+	const maxMismatchLog = 20
+
+	for cp := rune(0); cp <= 0x10FFFF; cp++ {
+		got := Wcwidth(cp)
+		switch {
+		case want[cp] == -1 && got != -1:
+			assigned++
+		case binarySearch(cp, unicode16WidthChanges):
+			if got == want[cp] {
+				t.Errorf("Wcwidth(%U) = %d, the same as glibc 2.36, want the Unicode 16.0.0 width", cp, got)
+			}
+		case got != want[cp]:
+			mismatches++
+			if mismatches <= maxMismatchLog {
+				t.Errorf("Wcwidth(%U) = %d, glibc 2.36 = %d", cp, got, want[cp])
+			}
+		}
+	}
+
+	if mismatches > maxMismatchLog {
+		t.Errorf("... and %d more mismatches (total: %d)", mismatches-maxMismatchLog, mismatches)
+	}
+	if assigned != unicode16NewCharacters {
+		t.Errorf("%d codepoints are -1 in glibc 2.36 but not in Wcwidth, want the %d characters added since Unicode 14.0.0",
+			assigned, unicode16NewCharacters)
+	}
+}
+
+func TestWcwidth(t *testing.T) {
+	tests := []struct {
+		name string
+		r    rune
+		want int
+	}{
+		{"NUL", 0x0000, 0},
+		{"tab", '\t', -1},
+		{"newline", '\n', -1},
+		{"DEL", 0x007F, -1},
+		{"C1 control", 0x0085, -1},
+		{"space", ' ', 1},
+		{"letter", 'a', 1},
+		{"soft hyphen", 0x00AD, 1},
+		{"combining acute", 0x0301, 0},
+		{"Devanagari vowel sign AA (Mc)", 0x093E, 1},
+		{"ZWJ", 0x200D, 0},
+		{"variation selector", 0xFE0F, 0},
+		{"line separator", 0x2028, -1},
+		{"paragraph separator", 0x2029, -1},
+		{"Arabic number sign", 0x0600, 1},
+		{"ambiguous plus-minus", '±', 1},
+		{"box drawing", '─', 1},
+		{"CJK", '世', 2},
+		{"Hangul syllable", '한', 2},
+		{"Hangul leading jamo", 0x1100, 2},
+		{"Hangul medial jamo", 0x1161, 0},
+		{"Hangul final jamo", 0x11A8, 0},
+		{"fullwidth A", 'Ａ', 2},
+		{"emoji", '😀', 2},
+		{"emoji modifier", 0x1F3FD, 2},
+		{"regional indicator", 0x1F1FA, 1},
+		{"text-default emoji", '☺', 1},
+		{"unassigned", 0x0378, -1},
+		{"private use", 0xE000, 1},
+		{"noncharacter", 0xFFFF, -1},
+		{"surrogate", 0xD800, -1},
+		{"max rune", 0x10FFFF, -1},
+		{"negative rune", -1, -1},
+		{"beyond max rune", 0x110000, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Wcwidth(tt.r); got != tt.want {
+				t.Errorf("Wcwidth(%U) = %d, want %d", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWcswidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ASCII", "Hello, World!", 13},
+		{"embedded NUL", "a\x00b", 2},
+		{"tab", "a\tb", -1},
+		{"newline", "line\n", -1},
+		{"DEL", "a\x7F", -1},
+		{"CJK", "Hello 世界", 10},
+		{"combining", "e\u0301", 1},
+		{"ambiguous", "±½", 2},
+		{"ZWJ family not merged", "👨‍👩‍👧", 6},
+		{"flag not merged", "🇺🇸", 2},
+		{"skin tone not merged", "👍🏽", 4},
+		{"VS16 ignored", "\u2764\uFE0F", 1},
+		{"NFD Hangul", "\u1100\u1161\u11A8", 2},
+		{"C1 control", "a\u0085", -1},
+		{"unassigned", "a\u0378", -1},
+		{"invalid UTF-8", "a\xffb", -1},
+		{"truncated UTF-8", "世"[:2], -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Wcswidth(tt.s); got != tt.want {
+				t.Errorf("Wcswidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}